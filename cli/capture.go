@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/viant/endly/cli/xunit"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/endly/service/system/exec"
+	"github.com/viant/endly/service/testing/runner/http"
+)
+
+// caseCapture represents tagged test case output captured from service events
+type caseCapture struct {
+	start       time.Time
+	end         time.Time
+	stdout      []string
+	stderr      []string
+	attachments []string
+}
+
+// captureOutput captures timings, exec/http output and attachments from supplied tag events
+func captureOutput(events []msg.Event) *caseCapture {
+	var result = &caseCapture{}
+	var attached = make(map[string]bool)
+	for _, event := range events {
+		if timestamp := event.Timestamp(); !timestamp.IsZero() {
+			if result.start.IsZero() || timestamp.Before(result.start) {
+				result.start = timestamp
+			}
+			if timestamp.After(result.end) {
+				result.end = timestamp
+			}
+		}
+		switch value := event.Value().(type) {
+		case *exec.StdinEvent:
+			result.stdout = append(result.stdout, fmt.Sprintf("%v$ %v", value.SessionID, value.Stdin))
+		case *exec.StdoutEvent:
+			if value.Stdout != "" {
+				result.stdout = append(result.stdout, value.Stdout)
+			}
+			if value.Error != "" {
+				result.stderr = append(result.stderr, value.Error)
+			}
+		case *msg.StreamEvent:
+			result.stdout = append(result.stdout, strings.TrimSuffix(value.Output, "\n"))
+		case *http.Request:
+			result.stdout = append(result.stdout, fmt.Sprintf("%v %v", value.Method, value.URL))
+			if value.Body != "" {
+				result.stdout = append(result.stdout, value.Body)
+			}
+		case *http.Response:
+			result.stdout = append(result.stdout, fmt.Sprintf("StatusCode: %v, time taken: %v ms", value.Code, value.TimeTakenMs))
+			if value.Body != "" {
+				result.stdout = append(result.stdout, value.Body)
+			}
+			if value.Error != "" {
+				result.stderr = append(result.stderr, value.Error)
+			}
+		}
+		if attachment, ok := event.Value().(msg.Attachment); ok {
+			for _, location := range attachment.Attachments() {
+				if location == "" || attached[location] {
+					continue
+				}
+				attached[location] = true
+				result.attachments = append(result.attachments, location)
+			}
+		}
+	}
+	return result
+}
+
+// apply sets test case timing, system out/err and attachments, attachments use Jenkins/GitLab [[ATTACHMENT|path]] convention
+func (c *caseCapture) apply(testCase *xunit.TestCase) {
+	if !c.start.IsZero() {
+		testCase.Start = c.start.Format(time.RFC3339Nano)
+		testCase.End = c.end.Format(time.RFC3339Nano)
+		testCase.Time = fmt.Sprintf("%.3f", c.end.Sub(c.start).Seconds())
+	}
+	var sysout = make([]string, 0)
+	if testCase.Sysout != "" {
+		sysout = append(sysout, testCase.Sysout)
+	}
+	sysout = append(sysout, c.stdout...)
+	for _, location := range c.attachments {
+		sysout = append(sysout, fmt.Sprintf("[[ATTACHMENT|%v]]", location))
+	}
+	testCase.Sysout = strings.Join(sysout, "\n")
+	if len(c.stderr) > 0 {
+		testCase.Syserr = strings.Join(c.stderr, "\n")
+	}
+}
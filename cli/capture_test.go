@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly/cli/xunit"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/endly/service/system/exec"
+	"github.com/viant/endly/service/testing/runner/http"
+	"github.com/viant/endly/service/workflow"
+)
+
+func TestCaptureOutput(t *testing.T) {
+	events := []msg.Event{
+		msg.NewEvent(exec.NewSdtinEvent("localhost", "ls /tmp")),
+		msg.NewEvent(exec.NewStdoutEvent("localhost", "abc.txt", errors.New("exit code 1"))),
+		msg.NewEvent(msg.NewStreamEvent("localhost", "line 1\nline 2\n", 1)),
+		msg.NewEvent(&http.Request{Method: "GET", URL: "http://127.0.0.1/health"}),
+		msg.NewEvent(&http.Response{Code: 200, Body: "ok", TimeTakenMs: 3}),
+		msg.NewEvent(workflow.NewLogFileEvent("/tmp/logs/0001_print.json")),
+		msg.NewEvent(workflow.NewLogFileEvent("/tmp/logs/0002_error.json")),
+		msg.NewEvent(workflow.NewLogFileEvent("/tmp/logs/0001_print.json")),
+	}
+	testCase := xunit.NewTestCase()
+	testCase.Sysout = "failure"
+	captureOutput(events).apply(testCase)
+	assert.Equal(t, `failure
+localhost$ ls /tmp
+abc.txt
+line 1
+line 2
+GET http://127.0.0.1/health
+StatusCode: 200, time taken: 3 ms
+ok
+[[ATTACHMENT|/tmp/logs/0001_print.json]]
+[[ATTACHMENT|/tmp/logs/0002_error.json]]`, testCase.Sysout)
+	assert.Equal(t, "exit code 1", testCase.Syserr)
+	assert.NotEmpty(t, testCase.Start)
+	assert.NotEmpty(t, testCase.End)
+	assert.Regexp(t, `^\d+\.\d{3}$`, testCase.Time)
+}
@@ -30,6 +30,17 @@ func (e *Event) AddEvent(event msg.Event) {
 	e.Events = append(e.Events, event)
 }
 
+// Attempts returns max action attempt recorded for this tag
+func (e *Event) Attempts() int {
+	var result = 0
+	for _, event := range e.Events {
+		if activity, ok := event.Value().(*model.Activity); ok && activity.Attempt > result {
+			result = activity.Attempt
+		}
+	}
+	return result
+}
+
 // Events represents tags
 type Events struct {
 	*model.Activities
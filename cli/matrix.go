@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/viant/endly/cli/xunit"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/endly/service/workflow"
+)
+
+func (r *Runner) processMatrixSummary(event msg.Event) {
+	summary, ok := event.Value().(*workflow.MatrixSummaryEvent)
+	if !ok {
+		return
+	}
+	for _, result := range summary.Results {
+		r.matrixSummary = append(r.matrixSummary, newMatrixTestsuite(summary.Workflow, result))
+	}
+}
+
+// newMatrixTestsuite creates matrix combination test suite with a test case per validation tag
+func newMatrixTestsuite(workflowName string, result *workflow.MatrixResult) *xunit.Testsuite {
+	suite := xunit.NewTestsuite()
+	suite.Name = fmt.Sprintf("%v[%v]", workflowName, result.Name)
+	suite.Time = fmt.Sprintf("%.3f", float64(result.ElapsedMs)/1000)
+	if result.Error != "" {
+		suite.Errors = "1"
+		suite.ErrorsDetail = result.Error
+	}
+	var testCases = make(map[string]*xunit.TestCase)
+	var passed, failed = make(map[string]int), make(map[string]int)
+	for _, validation := range result.Validations {
+		testCase, ok := testCases[validation.TagID]
+		if !ok {
+			testCase = xunit.NewTestCase()
+			testCase.Label = validation.TagID
+			testCase.Name = strings.Split(validation.Description, "\n")[0]
+			if testCase.Name == "" {
+				testCase.Name = validation.TagID
+			}
+			testCases[validation.TagID] = testCase
+			suite.TestCase = append(suite.TestCase, testCase)
+		}
+		passed[validation.TagID] += validation.PassedCount
+		failed[validation.TagID] += validation.FailedCount
+		if !validation.HasFailure() {
+			continue
+		}
+		testCase.FailuresDetail = validation.Report()
+		nodes := xunit.NewNodes()
+		nodes.Expected = "/"
+		nodes.Result = "/"
+		for _, failure := range validation.Failures {
+			node := xunit.NewNodes()
+			node.Expected = fmt.Sprintf("%s: %s", failure.Path, failure.Expected)
+			node.Result = fmt.Sprintf("%s: %s", failure.Path, failure.Actual)
+			node.Error = &xunit.Error{
+				Type:  failure.Reason,
+				Value: failure.Message,
+			}
+			nodes.Nodes = append(nodes.Nodes, node)
+		}
+		testCase.Nodes = nodes
+	}
+	var failedCases = 0
+	for tagID, testCase := range testCases {
+		testCase.Tests = fmt.Sprintf("%d", passed[tagID]+failed[tagID])
+		testCase.Failures = fmt.Sprintf("%d", failed[tagID])
+		if failed[tagID] > 0 {
+			failedCases++
+		}
+	}
+	suite.TestCases = fmt.Sprintf("%d", len(testCases))
+	suite.Reports = fmt.Sprintf("%d", len(testCases))
+	suite.Tests = fmt.Sprintf("%d", len(testCases))
+	suite.Failures = fmt.Sprintf("%d", failedCases)
+	return suite
+}
+
+// matrixTestsuites returns combined summary followed by matrix combination test suites
+func (r *Runner) matrixTestsuites() *xunit.Testsuites {
+	result := xunit.NewTestsuites()
+	result.Name = r.xUnitSummary.Name
+	result.Testsuite = append(result.Testsuite, r.xUnitSummary)
+	result.Testsuite = append(result.Testsuite, r.matrixSummary...)
+	var tests, failures, errors = 0, 0, 0
+	for _, suite := range r.matrixSummary {
+		tests++
+		if suite.Errors != "" {
+			errors++
+		} else if suite.Failures != "0" {
+			failures++
+		}
+	}
+	result.Tests = fmt.Sprintf("%d", tests)
+	result.Failures = fmt.Sprintf("%d", failures)
+	result.Errors = fmt.Sprintf("%d", errors)
+	return result
+}
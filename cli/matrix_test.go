@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/assertly"
+	"github.com/viant/endly/service/workflow"
+)
+
+func TestNewMatrixTestsuite(t *testing.T) {
+	combinations := workflow.ExpandMatrix(map[string][]interface{}{
+		"version": {"1.0", "1.1"},
+		"db":      {"mysql", "pg"},
+	})
+	var names = make([]string, 0)
+	for _, combination := range combinations {
+		names = append(names, combination.Name())
+	}
+	assert.EqualValues(t, []string{"db=mysql,version=1.0", "db=mysql,version=1.1", "db=pg,version=1.0", "db=pg,version=1.1"}, names)
+	assert.Equal(t, "db-pg_version-1.1", combinations[3].ID())
+
+	failed := &assertly.Validation{TagID: "Test1", Description: "check users", PassedCount: 1}
+	failed.AddFailure(assertly.NewFailure("", "/id", assertly.EqualViolation, 1, 2))
+	suite := newMatrixTestsuite("app", &workflow.MatrixResult{
+		Name:      combinations[0].Name(),
+		ElapsedMs: 1500,
+		Validations: []*assertly.Validation{
+			{TagID: "Test0", PassedCount: 2},
+			failed,
+		},
+	})
+	assert.Equal(t, "app[db=mysql,version=1.0]", suite.Name)
+	assert.Equal(t, "1.500", suite.Time)
+	assert.Equal(t, "2", suite.Tests)
+	assert.Equal(t, "1", suite.Failures)
+	if assert.Equal(t, 2, len(suite.TestCase)) {
+		assert.Equal(t, "Test0", suite.TestCase[0].Name)
+		assert.Equal(t, "2", suite.TestCase[0].Tests)
+		assert.Equal(t, "check users", suite.TestCase[1].Name)
+		assert.Equal(t, "1", suite.TestCase[1].Failures)
+		assert.Equal(t, 1, len(suite.TestCase[1].Nodes.Nodes))
+	}
+}
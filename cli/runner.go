@@ -50,6 +50,7 @@ type Runner struct {
 	*Events
 	request               *workflow.RunRequest
 	xUnitSummary          *xunit.Testsuite
+	matrixSummary         []*xunit.Testsuite
 	context               *endly.Context
 	filter                map[string]bool
 	manager               endly.Manager
@@ -59,6 +60,7 @@ type Runner struct {
 	hasValidationFailures bool
 	err                   error
 	group                 *MessageGroup
+	stream                *streamTail
 }
 
 func (r *Runner) printInput(output string) {
@@ -292,6 +294,9 @@ func (r *Runner) processActivityStart(event msg.Event) bool {
 		info = activity.Comments
 	}
 	serviceAction := fmt.Sprintf("%v.%v", activity.Service, activity.Action)
+	if activity.Attempt > 1 {
+		serviceAction = fmt.Sprintf("%v #%v", serviceAction, activity.Attempt)
+	}
 	r.printShortMessage(messageTypeAction, info, messageTypeAction, serviceAction)
 	return true
 }
@@ -308,6 +313,10 @@ func (r *Runner) processEvent(event msg.Event, filter map[string]bool) {
 		return
 	}
 	r.processActivityEnd(event)
+	r.processMatrixSummary(event)
+	if r.processStream(event, filter) {
+		return
+	}
 	if r.processActivityStart(event) {
 		return
 	}
@@ -562,17 +571,19 @@ func (r *Runner) reportTagSummary() {
 		if validation != nil {
 			useCase.FailuresDetail = validation.Report()
 		}
-		if len(tag.Events) > 0 {
-			useCase.Time = tag.Events[0].Timestamp().String()
+		if attempts := tag.Attempts(); attempts > 1 {
+			useCase.Attempts = fmt.Sprintf("%d", attempts)
 		}
 		if failureLog != nil {
 			useCase.Sysout = failureLog.JSONOutput
 		}
+		captureOutput(tag.Events).apply(useCase)
 	}
 	r.xUnitSummary.TestCases = fmt.Sprintf("%d", useCaseCount)
 	r.xUnitSummary.Reports = fmt.Sprintf("%d", useCaseCount)
 	r.xUnitSummary.Tests = fmt.Sprintf("%d", r.report.TotalTagPassed+r.report.TotalTagFailed)
 	r.xUnitSummary.Failures = fmt.Sprintf("%d", +r.report.TotalTagFailed)
+	r.xUnitSummary.Time = fmt.Sprintf("%.3f", float64(r.report.ElapsedMs)/1000)
 	if r.request != nil && len(r.request.Params) > 0 {
 		if val, ok := r.request.Params["app"]; ok {
 			r.xUnitSummary.Name = toolbox.AsString(val)
@@ -640,18 +651,24 @@ func (r *Runner) printSummary() {
 		return
 	}
 	var err error
+	var summary interface{} = r.xUnitSummary
+	var element = "test-suite"
+	if len(r.matrixSummary) > 0 {
+		summary = r.matrixTestsuites()
+		element = "test-suites"
+	}
 	buf := new(bytes.Buffer)
 	switch r.request.SummaryFormat {
 	case "xml":
 		encoder := xml.NewEncoder(buf)
 		encoder.Indent("  ", "    ")
-		err = encoder.EncodeElement(r.xUnitSummary, xml.StartElement{Name: xml.Name{Local: "test-suite"}})
+		err = encoder.EncodeElement(summary, xml.StartElement{Name: xml.Name{Local: element}})
 	case "yaml":
-		err = yaml.NewEncoder(buf).Encode(r.xUnitSummary)
+		err = yaml.NewEncoder(buf).Encode(summary)
 	case "json":
 		encoder := json.NewEncoder(buf)
 		encoder.SetIndent("  ", "    ")
-		err = encoder.Encode(r.xUnitSummary)
+		err = encoder.Encode(summary)
 	}
 	if err == nil {
 		err = ioutil.WriteFile(fmt.Sprintf("summary.%v", r.request.SummaryFormat), buf.Bytes(), 0644)
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/viant/endly/model/msg"
+)
+
+// streamTail represents rolling tail of streamed source output rendered in place
+type streamTail struct {
+	source   string
+	lines    []string
+	skipped  int
+	rendered int
+}
+
+// add appends output lines, keeps only tail last lines
+func (t *streamTail) add(output string, tail int) {
+	t.lines = append(t.lines, strings.Split(strings.TrimSuffix(output, "\n"), "\n")...)
+	if overflow := len(t.lines) - tail; overflow > 0 {
+		t.skipped += overflow
+		t.lines = append(t.lines[:0], t.lines[overflow:]...)
+	}
+}
+
+// processStream renders stream events with tail limit, replacing previously rendered tail lines
+func (r *Runner) processStream(event msg.Event, filter map[string]bool) bool {
+	stream, ok := event.Value().(*msg.StreamEvent)
+	if !ok {
+		r.stream = nil
+		return false
+	}
+	if stream.Tail <= 0 || !event.IsLoggable() || !r.canReport(event, filter) {
+		r.stream = nil
+		return false
+	}
+	if r.stream == nil || r.stream.source != stream.Source {
+		r.stream = &streamTail{source: stream.Source}
+		r.printShortMessage(msg.MessageStyleGeneric, stream.Source, msg.MessageStyleGeneric, "stream")
+	}
+	r.stream.add(stream.Output, stream.Tail)
+	if r.stream.rendered > 0 {
+		r.Printf("\033[%dA\033[J", r.stream.rendered) //move cursor up and clear previous tail
+	}
+	r.stream.rendered = len(r.stream.lines)
+	if r.stream.skipped > 0 {
+		r.Printf("%v\n", r.ColorText(fmt.Sprintf("... %v lines skipped", r.stream.skipped), r.MessageStyleColor[msg.MessageStyleGeneric]))
+		r.stream.rendered++
+	}
+	columns := r.Columns()
+	for _, line := range r.stream.lines {
+		if len(line) > columns { //wrapped line would break cursor movement
+			line = line[:columns]
+		}
+		r.Printf("%v\n", r.ColorText(line, r.MessageStyleColor[msg.MessageStyleOutput]))
+	}
+	return true
+}
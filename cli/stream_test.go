@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/endly/service/system/exec"
+)
+
+func TestRunner_ProcessStream(t *testing.T) {
+	buf := new(bytes.Buffer)
+	runner := New()
+	runner.Renderer = NewRenderer(buf, 120)
+	publish := func(value interface{}) {
+		event := msg.NewEvent(value)
+		event.SetLoggable(true)
+		runner.processEvent(event, WildcardFilter())
+	}
+	publish(msg.NewStreamEvent("localhost", "a\nb\n", 2))
+	assert.Equal(t, 2, runner.stream.rendered)
+	publish(msg.NewStreamEvent("localhost", "c\n", 2))
+	assert.Equal(t, []string{"b", "c"}, runner.stream.lines)
+	assert.Equal(t, 1, runner.stream.skipped)
+	assert.Equal(t, 3, runner.stream.rendered)
+	publish(msg.NewStreamEvent("localhost", "d\ne\nf\n", 2))
+	assert.Equal(t, []string{"e", "f"}, runner.stream.lines)
+	assert.Equal(t, 4, runner.stream.skipped)
+	output := buf.String()
+	assert.Equal(t, 1, strings.Count(output, "stream"))
+	assert.True(t, strings.Contains(output, "\033[2A\033[J"))
+	assert.True(t, strings.Contains(output, "\033[3A\033[J"))
+	assert.True(t, strings.Contains(output, "... 4 lines skipped"))
+
+	publish(exec.NewSdtinEvent("localhost", "ls"))
+	assert.Nil(t, runner.stream)
+}
@@ -16,6 +16,9 @@ type TestCase struct {
 	TestCases      string `xml:"test-cases,attr,omitempty"  yaml:"test-cases,omitempty"  json:"test-cases,omitempty"`
 	Reports        string `xml:"reports,attr,omitempty"  yaml:"reports,omitempty"  json:"reports,omitempty"`
 	Time           string `xml:"time,attr,omitempty"  yaml:"time,omitempty"  json:"time,omitempty"`
+	Start          string `xml:"start,attr,omitempty"  yaml:"start,omitempty"  json:"start,omitempty"`
+	End            string `xml:"end,attr,omitempty"  yaml:"end,omitempty"  json:"end,omitempty"`
+	Attempts       string `xml:"attempts,attr,omitempty"  yaml:"attempts,omitempty"  json:"attempts,omitempty"`
 	Nodes          *Nodes `xml:"nodes,omitempty"  yaml:"nodes,omitempty"  json:"nodes,omitempty"`
 	Sysout         string `xml:"sysout,omitempty"  yaml:"sysout,omitempty"  json:"sysout,omitempty"`
 	Syserr         string `xml:"syserr,omitempty"  yaml:"syserr,omitempty"  json:"syserr,omitempty"`
@@ -23,3 +23,21 @@ func NewTestsuite() *Testsuite {
 		TestCase: make([]*TestCase, 0),
 	}
 }
+
+// Testsuites represents a collection of test-suite nodes, i.e. combined and per matrix combination summaries
+type Testsuites struct {
+	Name     string `xml:"name,attr,omitempty" yaml:"name,omitempty"  json:"name,omitempty" `
+	Errors   string `xml:"errors,attr,omitempty" yaml:"errors,omitempty"  json:"errors,omitempty" `
+	Failures string `xml:"failures,attr,omitempty" yaml:"failures,omitempty"  json:"failures,omitempty" `
+	Tests    string `xml:"tests,attr" yaml:"tests,omitempty"  json:"tests,omitempty" `
+	Time     string `xml:"time,attr,omitempty" yaml:"time,omitempty"  json:"time,omitempty" `
+
+	Testsuite []*Testsuite `xml:"test-suite" yaml:"test-suite,omitempty"  json:"test-suite,omitempty" `
+}
+
+// NewTestsuites creates a new test suites
+func NewTestsuites() *Testsuites {
+	return &Testsuites{
+		Testsuite: make([]*Testsuite, 0),
+	}
+}
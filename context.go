@@ -35,6 +35,7 @@ type Context struct {
 	SessionID       string
 	CLIEnabled      bool
 	HasLogger       bool
+	LogDirectory    string
 	AsyncUnsafeKeys map[interface{}]bool
 	Secrets         *secret.Service
 	Wait            *sync.WaitGroup
@@ -58,6 +59,50 @@ func (c *Context) Background() context.Context {
 	return c.context
 }
 
+type timeoutKey struct{}
+
+// WithTimeout sets go context deadline for supplied node, returned function cancels it and restores previous go context
+func (c *Context) WithTimeout(node string, timeout time.Duration) func() {
+	parent := c.Background()
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	if deadline, ok := parent.Deadline(); !ok || time.Now().Add(timeout).Before(deadline) {
+		ctx = context.WithValue(ctx, timeoutKey{}, &TimeoutError{Node: node, Timeout: timeout})
+	}
+	c.context = ctx
+	return func() {
+		cancel()
+		c.context = parent
+	}
+}
+
+// WithListener returns context copy sharing state, services and go context with supplied event listener
+func (c *Context) WithListener(listener msg.Listener) *Context {
+	result := *c
+	result.Listener = listener
+	return &result
+}
+
+// Detach removes go context deadline, returned function restores it
+func (c *Context) Detach() func() {
+	parent := c.Background()
+	c.context = context.Background()
+	return func() {
+		c.context = parent
+	}
+}
+
+// TimeoutError returns timeout error if go context deadline has been exceeded
+func (c *Context) TimeoutError() error {
+	ctx := c.Background()
+	if ctx.Err() == nil {
+		return nil
+	}
+	if err, ok := ctx.Value(timeoutKey{}).(*TimeoutError); ok {
+		return err
+	}
+	return ctx.Err()
+}
+
 // Publish publishes event to listeners, it updates current run details like activity workflow name etc ...
 func (c *Context) Publish(value interface{}) msg.Event {
 	event, ok := value.(msg.Event)
@@ -102,9 +147,12 @@ func (c *Context) Clone() *Context {
 	result.state = NewDefaultState(c)
 	result.state.Apply(c.state)
 	result.SessionID = c.SessionID
+	result.context = c.context
 	result.Listener = c.Listener
 	result.CLIEnabled = c.CLIEnabled
+	result.LogDirectory = c.LogDirectory
 	result.Secrets = c.Secrets
+	result.Debugger = c.Debugger
 	result.AsyncUnsafeKeys = make(map[interface{}]bool)
 	for k, v := range c.AsyncUnsafeKeys {
 		result.AsyncUnsafeKeys[k] = v
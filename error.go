@@ -3,6 +3,7 @@ package endly
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Error represents an workflow execution error
@@ -33,3 +34,14 @@ func NewError(service, action string, err error) error {
 		error: err,
 	}
 }
+
+// TimeoutError represents workflow node (workflow, task or action) timeout error
+type TimeoutError struct {
+	Node    string
+	Timeout time.Duration
+}
+
+// Error returns an error
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%v timed out after %v", e.Node, e.Timeout)
+}
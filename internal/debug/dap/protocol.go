@@ -0,0 +1,131 @@
+package dap
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+const contentLengthHeader = "Content-Length"
+
+type (
+	// message represents incoming Debug Adapter Protocol request
+	message struct {
+		Seq       int             `json:"seq"`
+		Type      string          `json:"type"`
+		Command   string          `json:"command"`
+		Arguments json.RawMessage `json:"arguments,omitempty"`
+	}
+
+	response struct {
+		Seq        int         `json:"seq"`
+		Type       string      `json:"type"`
+		RequestSeq int         `json:"request_seq"`
+		Success    bool        `json:"success"`
+		Command    string      `json:"command"`
+		Message    string      `json:"message,omitempty"`
+		Body       interface{} `json:"body,omitempty"`
+	}
+
+	event struct {
+		Seq   int         `json:"seq"`
+		Type  string      `json:"type"`
+		Event string      `json:"event"`
+		Body  interface{} `json:"body,omitempty"`
+	}
+
+	source struct {
+		Name string `json:"name,omitempty"`
+		Path string `json:"path,omitempty"`
+	}
+
+	sourceBreakpoint struct {
+		Line int `json:"line"`
+	}
+
+	setBreakpointsArguments struct {
+		Source      source             `json:"source"`
+		Breakpoints []sourceBreakpoint `json:"breakpoints"`
+		Lines       []int              `json:"lines"`
+	}
+
+	breakpoint struct {
+		Verified bool    `json:"verified"`
+		Line     int     `json:"line,omitempty"`
+		Message  string  `json:"message,omitempty"`
+		Source   *source `json:"source,omitempty"`
+	}
+
+	variablesArguments struct {
+		VariablesReference int `json:"variablesReference"`
+	}
+
+	evaluateArguments struct {
+		Expression string `json:"expression"`
+		Context    string `json:"context"`
+	}
+
+	stackFrame struct {
+		ID     int     `json:"id"`
+		Name   string  `json:"name"`
+		Source *source `json:"source,omitempty"`
+		Line   int     `json:"line"`
+		Column int     `json:"column"`
+	}
+
+	scope struct {
+		Name               string `json:"name"`
+		VariablesReference int    `json:"variablesReference"`
+		Expensive          bool   `json:"expensive"`
+	}
+
+	variable struct {
+		Name               string `json:"name"`
+		Value              string `json:"value"`
+		Type               string `json:"type,omitempty"`
+		VariablesReference int    `json:"variablesReference"`
+	}
+
+	thread struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+)
+
+// readMessage reads Content-Length framed protocol message
+func readMessage(reader *bufio.Reader) (*message, error) {
+	headers, err := textproto.NewReader(reader).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+	length, err := strconv.Atoi(strings.TrimSpace(headers.Get(contentLengthHeader)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %v header: %w", contentLengthHeader, err)
+	}
+	payload := make([]byte, length)
+	if _, err = io.ReadFull(reader, payload); err != nil {
+		return nil, err
+	}
+	result := &message{}
+	if err = json.Unmarshal(payload, result); err != nil {
+		return nil, fmt.Errorf("failed to decode message: %w", err)
+	}
+	return result, nil
+}
+
+// writeMessage writes Content-Length framed protocol message
+func writeMessage(writer io.Writer, msg interface{}) error {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, err = fmt.Fprintf(writer, "%v: %d\r\n\r\n", contentLengthHeader, len(payload)); err != nil {
+		return err
+	}
+	_, err = writer.Write(payload)
+	return err
+}
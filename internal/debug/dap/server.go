@@ -0,0 +1,526 @@
+package dap
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/viant/endly/internal/debug"
+	"github.com/viant/endly/model/graph"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+)
+
+const (
+	threadID   = 1
+	frameID    = 1
+	threadName = "workflow"
+)
+
+const (
+	stateReference = iota + 1
+	requestReference
+	responseReference
+	firstDynamicReference
+)
+
+// Server represents Debug Adapter Protocol server controlling workflow debugger, it serves a single client
+type Server struct {
+	debugger   *debug.Debugger
+	graph      *graph.Service
+	listener   net.Listener
+	conn       net.Conn
+	writeMux   sync.Mutex
+	seq        int
+	mux        sync.Mutex
+	sources    map[string]*workflowSource
+	stop       *debug.Stop
+	references map[int]interface{}
+	nextRef    int
+	pauseMux   sync.Mutex
+	resume     chan debug.Decision
+	configured chan struct{}
+	configOnce sync.Once
+	done       chan struct{}
+	doneOnce   sync.Once
+}
+
+// workflowSource represents workflow YAML file with resolved breakpoints
+type workflowSource struct {
+	path        string
+	workflow    string
+	positions   []*graph.Position
+	breakpoints []debug.Step
+}
+
+type launchArguments struct {
+	StopOnEntry bool `json:"stopOnEntry"`
+}
+
+// Listen starts listening on supplied TCP address
+func (s *Server) Listen(address string) (err error) {
+	s.listener, err = net.Listen("tcp", address)
+	return err
+}
+
+// Addr returns server listener address
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
+// Serve accepts debug client connection, it blocks till the client finished configuration or disconnected
+func (s *Server) Serve() error {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return fmt.Errorf("failed to accept debug client: %w", err)
+	}
+	s.conn = conn
+	go s.handle(conn)
+	select {
+	case <-s.configured:
+	case <-s.done:
+	}
+	return nil
+}
+
+// Close notifies client that debugged workflow terminated and closes the server
+func (s *Server) Close() error {
+	if s.conn != nil {
+		s.sendEvent("terminated", nil)
+	}
+	s.shutdown()
+	if s.conn != nil {
+		_ = s.conn.Close()
+	}
+	return s.listener.Close()
+}
+
+// Paused notifies client about stopped step and waits for client decision
+func (s *Server) Paused(stop *debug.Stop) debug.Decision {
+	s.pauseMux.Lock()
+	defer s.pauseMux.Unlock()
+	select {
+	case <-s.done:
+		return debug.Proceed
+	default:
+	}
+	s.mux.Lock()
+	s.stop = stop
+	s.references = make(map[int]interface{})
+	s.nextRef = firstDynamicReference
+	s.mux.Unlock()
+	reason := "step"
+	if stop.Error != nil {
+		reason = "exception"
+	} else if stop.Before && s.debugger.HasBreakpoint(stop.Step) {
+		reason = "breakpoint"
+	}
+	s.sendEvent("stopped", map[string]interface{}{
+		"reason":            reason,
+		"description":       describe(stop),
+		"threadId":          threadID,
+		"allThreadsStopped": true,
+	})
+	select {
+	case decision := <-s.resume:
+		return decision
+	case <-s.done:
+		return debug.Proceed
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer s.shutdown()
+	reader := bufio.NewReader(conn)
+	for {
+		request, err := readMessage(reader)
+		if err != nil {
+			return
+		}
+		if request.Type != "request" {
+			continue
+		}
+		body, err := s.dispatch(request)
+		s.respond(request, body, err)
+		switch request.Command {
+		case "initialize":
+			s.sendEvent("initialized", nil)
+		case "disconnect", "terminate":
+			return
+		}
+	}
+}
+
+func (s *Server) dispatch(request *message) (interface{}, error) {
+	switch request.Command {
+	case "initialize":
+		return map[string]interface{}{
+			"supportsConfigurationDoneRequest": true,
+			"supportsEvaluateForHovers":        true,
+			"supportsRestartFrame":             true,
+			"supportsTerminateRequest":         true,
+		}, nil
+	case "launch", "attach":
+		args := &launchArguments{}
+		if len(request.Arguments) > 0 {
+			if err := json.Unmarshal(request.Arguments, args); err != nil {
+				return nil, err
+			}
+		}
+		if args.StopOnEntry {
+			s.debugger.Next()
+		}
+		return nil, nil
+	case "setBreakpoints":
+		args := &setBreakpointsArguments{}
+		if err := json.Unmarshal(request.Arguments, args); err != nil {
+			return nil, err
+		}
+		breakpoints, err := s.setBreakpoints(args)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"breakpoints": breakpoints}, nil
+	case "setExceptionBreakpoints", "setFunctionBreakpoints":
+		return map[string]interface{}{"breakpoints": []*breakpoint{}}, nil
+	case "configurationDone":
+		s.configOnce.Do(func() { close(s.configured) })
+		return nil, nil
+	case "threads":
+		return map[string]interface{}{"threads": []*thread{{ID: threadID, Name: threadName}}}, nil
+	case "stackTrace":
+		frames := s.stackTrace()
+		return map[string]interface{}{"stackFrames": frames, "totalFrames": len(frames)}, nil
+	case "scopes":
+		return map[string]interface{}{"scopes": s.scopes()}, nil
+	case "variables":
+		args := &variablesArguments{}
+		if err := json.Unmarshal(request.Arguments, args); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"variables": s.variables(args.VariablesReference)}, nil
+	case "evaluate":
+		args := &evaluateArguments{}
+		if err := json.Unmarshal(request.Arguments, args); err != nil {
+			return nil, err
+		}
+		result, err := s.evaluate(args.Expression)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"result": result.Value, "type": result.Type, "variablesReference": result.VariablesReference}, nil
+	case "continue":
+		s.debugger.Continue()
+		s.proceed(debug.Proceed)
+		return map[string]interface{}{"allThreadsContinued": true}, nil
+	case "next", "stepIn", "stepOut":
+		s.debugger.Next()
+		s.proceed(debug.Proceed)
+		return nil, nil
+	case "restartFrame":
+		if stop := s.currentStop(); stop == nil || stop.Before {
+			return nil, fmt.Errorf("action has not been executed yet")
+		}
+		s.proceed(debug.Rerun)
+		return nil, nil
+	case "pause":
+		s.debugger.Next()
+		return nil, nil
+	case "disconnect", "terminate":
+		s.debugger.Detach()
+		s.proceed(debug.Proceed)
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unsupported command: %v", request.Command)
+}
+
+func (s *Server) setBreakpoints(args *setBreakpointsArguments) ([]*breakpoint, error) {
+	lines := args.Lines
+	if len(args.Breakpoints) > 0 {
+		lines = make([]int, 0, len(args.Breakpoints))
+		for _, item := range args.Breakpoints {
+			lines = append(lines, item.Line)
+		}
+	}
+	aSource, err := s.loadSource(args.Source.Path)
+	if err != nil {
+		return nil, err
+	}
+	for _, step := range aSource.breakpoints {
+		s.debugger.RemoveBreakpoint(step)
+	}
+	aSource.breakpoints = nil
+	var result = make([]*breakpoint, 0, len(lines))
+	for _, line := range lines {
+		position := graph.ActionAt(aSource.positions, line)
+		if position == nil {
+			result = append(result, &breakpoint{Line: line, Message: "no action declared at line"})
+			continue
+		}
+		step := debug.Step{Workflow: aSource.workflow, Action: position.Name}
+		s.debugger.SetBreakpoint(step)
+		aSource.breakpoints = append(aSource.breakpoints, step)
+		result = append(result, &breakpoint{Verified: true, Line: position.Line, Source: &args.Source})
+	}
+	return result, nil
+}
+
+func (s *Server) loadSource(path string) (*workflowSource, error) {
+	workflow, err := s.graph.LoadWorkflow(context.Background(), path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load workflow: %v, %w", path, err)
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	result, ok := s.sources[path]
+	if !ok {
+		result = &workflowSource{path: path}
+		s.sources[path] = result
+	}
+	result.workflow = workflow.Name
+	result.positions = workflow.Positions()
+	return result, nil
+}
+
+// locate returns source and line of supplied step action
+func (s *Server) locate(step debug.Step) (*source, int) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	for _, candidate := range s.sources {
+		if candidate.workflow != step.Workflow {
+			continue
+		}
+		for _, position := range candidate.positions {
+			if position.Type == graph.TypeAction && position.Name == step.Action {
+				return &source{Name: candidate.workflow, Path: candidate.path}, position.Line
+			}
+		}
+	}
+	return nil, 0
+}
+
+func (s *Server) stackTrace() []*stackFrame {
+	stop := s.currentStop()
+	if stop == nil {
+		return []*stackFrame{}
+	}
+	frame := &stackFrame{ID: frameID, Name: describe(stop), Column: 1}
+	frame.Source, frame.Line = s.locate(stop.Step)
+	return []*stackFrame{frame}
+}
+
+func (s *Server) scopes() []*scope {
+	stop := s.currentStop()
+	if stop == nil {
+		return []*scope{}
+	}
+	var result = []*scope{
+		{Name: "State", VariablesReference: stateReference},
+		{Name: "Request", VariablesReference: requestReference},
+	}
+	if !stop.Before {
+		result = append(result, &scope{Name: "Response", VariablesReference: responseReference})
+	}
+	return result
+}
+
+func (s *Server) variables(reference int) []*variable {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	var result = make([]*variable, 0)
+	if s.stop == nil {
+		return result
+	}
+	var value interface{}
+	switch reference {
+	case stateReference:
+		value = map[string]interface{}(s.stop.State)
+	case requestReference:
+		value = s.stop.Request
+	case responseReference:
+		value = s.stop.Response
+		if s.stop.Error != nil {
+			value = map[string]interface{}{"error": s.stop.Error.Error()}
+		}
+	default:
+		value = s.references[reference]
+	}
+	value = normalize(value)
+	switch {
+	case toolbox.IsMap(value):
+		aMap := toolbox.AsMap(value)
+		var keys = make([]string, 0, len(aMap))
+		for k, v := range aMap {
+			if toolbox.IsFunc(v) {
+				continue
+			}
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			result = append(result, s.variable(key, aMap[key]))
+		}
+	case toolbox.IsSlice(value):
+		for i, item := range toolbox.AsSlice(value) {
+			result = append(result, s.variable(fmt.Sprintf("[%d]", i), item))
+		}
+	}
+	return result
+}
+
+func (s *Server) evaluate(expression string) (*variable, error) {
+	stop := s.currentStop()
+	if stop == nil {
+		return nil, fmt.Errorf("workflow is not paused")
+	}
+	expression = strings.TrimSpace(expression)
+	var value interface{}
+	if strings.Contains(expression, "$") {
+		value = stop.State.Expand(expression)
+	} else {
+		var ok bool
+		if value, ok = stop.State.GetValue(expression); !ok {
+			return nil, fmt.Errorf("%v: undefined", expression)
+		}
+	}
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.variable(expression, value), nil
+}
+
+// variable creates variable, container values get a reference to be expanded by client, callers hold s.mux
+func (s *Server) variable(name string, value interface{}) *variable {
+	value = normalize(value)
+	result := &variable{Name: name, Type: fmt.Sprintf("%T", value)}
+	switch {
+	case value == nil:
+		result.Value = "null"
+		result.Type = ""
+	case toolbox.IsMap(value):
+		result.Value = fmt.Sprintf("{%d}", len(toolbox.AsMap(value)))
+		result.VariablesReference = s.reference(value)
+	case toolbox.IsSlice(value):
+		result.Value = fmt.Sprintf("[%d]", len(toolbox.AsSlice(value)))
+		result.VariablesReference = s.reference(value)
+	default:
+		result.Value = toolbox.AsString(value)
+	}
+	return result
+}
+
+func (s *Server) reference(value interface{}) int {
+	if s.references == nil {
+		s.references = make(map[int]interface{})
+	}
+	if s.nextRef < firstDynamicReference {
+		s.nextRef = firstDynamicReference
+	}
+	result := s.nextRef
+	s.nextRef++
+	s.references[result] = value
+	return result
+}
+
+func (s *Server) currentStop() *debug.Stop {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.stop
+}
+
+// proceed resumes paused step with supplied decision
+func (s *Server) proceed(decision debug.Decision) {
+	s.mux.Lock()
+	if s.stop == nil {
+		s.mux.Unlock()
+		return
+	}
+	s.stop = nil
+	s.references = nil
+	s.mux.Unlock()
+	s.resume <- decision
+}
+
+func (s *Server) shutdown() {
+	s.doneOnce.Do(func() {
+		s.debugger.Detach()
+		close(s.done)
+	})
+}
+
+func (s *Server) respond(request *message, body interface{}, err error) {
+	result := &response{Type: "response", RequestSeq: request.Seq, Command: request.Command, Success: err == nil, Body: body}
+	if err != nil {
+		result.Message = err.Error()
+	}
+	s.send(func(seq int) interface{} {
+		result.Seq = seq
+		return result
+	})
+}
+
+func (s *Server) sendEvent(name string, body interface{}) {
+	s.send(func(seq int) interface{} {
+		return &event{Seq: seq, Type: "event", Event: name, Body: body}
+	})
+}
+
+func (s *Server) send(build func(seq int) interface{}) {
+	s.writeMux.Lock()
+	defer s.writeMux.Unlock()
+	if s.conn == nil {
+		return
+	}
+	s.seq++
+	_ = writeMessage(s.conn, build(s.seq))
+}
+
+func describe(stop *debug.Stop) string {
+	if stop.Before {
+		return fmt.Sprintf("before %v", stop.Step)
+	}
+	if stop.Error != nil {
+		return fmt.Sprintf("failed %v: %v", stop.Step, stop.Error)
+	}
+	return fmt.Sprintf("after %v", stop.Step)
+}
+
+// normalize converts struct values into generic maps
+func normalize(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	if aMap, ok := value.(data.Map); ok {
+		return map[string]interface{}(aMap)
+	}
+	if !toolbox.IsStruct(value) {
+		return value
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%T", value)
+	}
+	var result interface{}
+	if err = json.Unmarshal(encoded, &result); err != nil {
+		return fmt.Sprintf("%T", value)
+	}
+	return result
+}
+
+// New creates Debug Adapter Protocol server for supplied debugger
+func New(debugger *debug.Debugger) *Server {
+	result := &Server{
+		debugger:   debugger,
+		graph:      graph.New(),
+		sources:    make(map[string]*workflowSource),
+		resume:     make(chan debug.Decision, 1),
+		configured: make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	debugger.Controller = result
+	return result
+}
@@ -0,0 +1,176 @@
+package dap
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/textproto"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly/internal/debug"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+)
+
+type testClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	seq    int
+}
+
+func (c *testClient) call(command string, args interface{}) (map[string]interface{}, error) {
+	c.seq++
+	request := map[string]interface{}{"seq": c.seq, "type": "request", "command": command}
+	if args != nil {
+		request["arguments"] = args
+	}
+	if err := writeMessage(c.conn, request); err != nil {
+		return nil, err
+	}
+	return c.await(command)
+}
+
+// await reads messages till response for supplied command or event with supplied name
+func (c *testClient) await(name string) (map[string]interface{}, error) {
+	for {
+		_ = c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		headers, err := textproto.NewReader(c.reader).ReadMIMEHeader()
+		if err != nil {
+			return nil, err
+		}
+		length, _ := strconv.Atoi(headers.Get(contentLengthHeader))
+		payload := make([]byte, length)
+		if _, err = io.ReadFull(c.reader, payload); err != nil {
+			return nil, err
+		}
+		var result = make(map[string]interface{})
+		if err = json.Unmarshal(payload, &result); err != nil {
+			return nil, err
+		}
+		if result["command"] == name || result["event"] == name {
+			return result, nil
+		}
+	}
+}
+
+func body(msg map[string]interface{}) data.Map {
+	return data.Map(toolbox.AsMap(msg["body"]))
+}
+
+func TestServer(t *testing.T) {
+	debugger := debug.NewDebugger()
+	server := New(debugger)
+	if !assert.Nil(t, server.Listen("127.0.0.1:0")) {
+		return
+	}
+	defer server.Close()
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve()
+	}()
+	conn, err := net.Dial("tcp", server.Addr().String())
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer conn.Close()
+	client := &testClient{conn: conn, reader: bufio.NewReader(conn)}
+
+	_, err = client.call("initialize", map[string]interface{}{"adapterID": "endly"})
+	assert.Nil(t, err)
+	path, _ := filepath.Abs(filepath.Join("testdata", "app.yaml"))
+	response, err := client.call("setBreakpoints", map[string]interface{}{
+		"source":      map[string]interface{}{"path": path},
+		"breakpoints": []interface{}{map[string]interface{}{"line": 12}, map[string]interface{}{"line": 1}},
+	})
+	if !assert.Nil(t, err) || !assert.Equal(t, true, response["success"], response["message"]) {
+		return
+	}
+	breakpoints := toolbox.AsSlice(body(response)["breakpoints"])
+	assert.EqualValues(t, []interface{}{
+		map[string]interface{}{"verified": true, "line": float64(10), "source": map[string]interface{}{"path": path}},
+		map[string]interface{}{"verified": false, "line": float64(1), "message": "no action declared at line"},
+	}, breakpoints)
+	_, err = client.call("configurationDone", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, <-served)
+
+	state := data.NewMap()
+	state.Put("name", "endly")
+	state.Put("build", map[string]interface{}{"version": "1.0"})
+	assert.Equal(t, debug.Proceed, debugger.Before(debug.Step{Workflow: "app", TaskName: "checkout", Action: "checkout"}, nil, state))
+
+	decision := make(chan debug.Decision, 1)
+	step := debug.Step{Workflow: "app", TaskName: "compile", Action: "compile"}
+	go func() {
+		decision <- debugger.Before(step, map[string]interface{}{"commands": []interface{}{"go build"}}, state)
+	}()
+	stopped, err := client.await("stopped")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "breakpoint", toolbox.AsString(body(stopped)["reason"]))
+
+	response, err = client.call("stackTrace", map[string]interface{}{"threadId": threadID})
+	assert.Nil(t, err)
+	frames := toolbox.AsSlice(body(response)["stackFrames"])
+	if assert.Equal(t, 1, len(frames)) {
+		frame := data.Map(toolbox.AsMap(frames[0]))
+		assert.EqualValues(t, 10, frame.GetInt("line"))
+		assert.Equal(t, "before app/compile/compile", frame.GetString("name"))
+	}
+
+	response, err = client.call("variables", map[string]interface{}{"variablesReference": stateReference})
+	assert.Nil(t, err)
+	variables := toolbox.AsSlice(body(response)["variables"])
+	if assert.Equal(t, 2, len(variables)) {
+		build := data.Map(toolbox.AsMap(variables[0]))
+		assert.Equal(t, "build", build.GetString("name"))
+		response, err = client.call("variables", map[string]interface{}{"variablesReference": build.GetInt("variablesReference")})
+		assert.Nil(t, err)
+		assert.EqualValues(t, []interface{}{
+			map[string]interface{}{"name": "version", "value": "1.0", "type": "string", "variablesReference": float64(0)},
+		}, body(response)["variables"])
+	}
+
+	response, err = client.call("evaluate", map[string]interface{}{"expression": "$name-$build.version", "context": "repl"})
+	assert.Nil(t, err)
+	assert.Equal(t, "endly-1.0", toolbox.AsString(body(response)["result"]))
+
+	response, err = client.call("restartFrame", map[string]interface{}{"frameId": frameID})
+	assert.Nil(t, err)
+	assert.Equal(t, false, response["success"])
+
+	_, err = client.call("next", map[string]interface{}{"threadId": threadID})
+	assert.Nil(t, err)
+	assert.Equal(t, debug.Proceed, <-decision)
+
+	go func() {
+		decision <- debugger.After(step, nil, map[string]interface{}{"status": "ok"}, nil, state)
+	}()
+	stopped, err = client.await("stopped")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "step", toolbox.AsString(body(stopped)["reason"]))
+	_, err = client.call("restartFrame", map[string]interface{}{"frameId": frameID})
+	assert.Nil(t, err)
+	assert.Equal(t, debug.Rerun, <-decision)
+
+	go func() {
+		decision <- debugger.After(step, nil, map[string]interface{}{"status": "ok"}, nil, state)
+	}()
+	_, err = client.await("stopped")
+	assert.Nil(t, err)
+	_, err = client.call("continue", map[string]interface{}{"threadId": threadID})
+	assert.Nil(t, err)
+	assert.Equal(t, debug.Proceed, <-decision)
+	assert.Equal(t, debug.Proceed, debugger.Before(debug.Step{Workflow: "app", Action: "test"}, nil, state))
+
+	_, err = client.call("disconnect", nil)
+	assert.Nil(t, err)
+}
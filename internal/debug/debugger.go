@@ -1,73 +1,339 @@
 package debug
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
 	"sync"
+
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
 )
 
-// Debugger is responsible for debugging Endly workflows.
+// Decision represents debugger decision how to proceed with current step
+type Decision int
+
+const (
+	//Proceed continues execution
+	Proceed Decision = iota
+	//Skip skips current action
+	Skip
+	//Rerun re-runs current action
+	Rerun
+)
+
+// Controller handles paused execution instead of the interactive prompt, i.e. remote debug adapter
+type Controller interface {
+	// Paused blocks till the client decides how to proceed with the stopped step
+	Paused(stop *Stop) Decision
+}
+
+// Stop represents paused execution point
+type Stop struct {
+	Step     Step
+	Before   bool
+	Request  interface{}
+	Response interface{}
+	Error    error
+	State    data.Map
+}
+
+const debuggerHelp = `commands:
+  c            continue till next breakpoint
+  n            step to the next action
+  s            skip current action (before execution only)
+  r            re-run current action (after execution only)
+  p [key]      print state keys or state value for the key
+  set key=val  set state value, JSON values are decoded
+  e expr       evaluate $expression against the state
+  req          print current request
+  out          print current response or error
+  b selector   add breakpoint: workflow/task/action or #tagID
+  d selector   delete breakpoint
+  l            list breakpoints
+  q            quit debugger and continue
+`
+
+// Debugger is responsible for debugging Endly workflows, it is shared by cloned contexts running concurrently,
+// so its state is guarded by mutex and only one paused step is handled at a time.
 type Debugger struct {
-	mux          sync.Mutex
-	Breakpoints  map[Step]struct{} // Task names where the debugger will pause execution
-	StepMode     bool              // Flag to step through the workflow one task at a time
-	continueExec chan bool         // Channel used to control execution flow
+	mux         sync.Mutex
+	stop        sync.Mutex        // serializes paused steps handling
+	Breakpoints map[Step]struct{} // Step selectors where the debugger will pause execution
+	StepMode    bool              // Flag to step through the workflow one action at a time, use EnableStepMode once debugger runs
+	In          io.Reader
+	Out         io.Writer
+	Controller  Controller // optional controller replacing stdin prompt
+	scanner     *bufio.Scanner
+	paused      map[Step]int // steps paused before execution, pending After
+	disabled    bool
 }
 
-// NewDebugger creates a new debugger instance with initialized channels.
+// NewDebugger creates a new debugger instance reading commands from stdin.
 func NewDebugger() *Debugger {
 	return &Debugger{
-		Breakpoints:  make(map[Step]struct{}),
-		continueExec: make(chan bool),
+		Breakpoints: make(map[Step]struct{}),
+		paused:      make(map[Step]int),
+		In:          os.Stdin,
+		Out:         os.Stderr,
 	}
 }
 
-// SetBreakpoint sets a breakpoint on a task by its name.
+// SetBreakpoint sets a breakpoint with step selector.
 func (d *Debugger) SetBreakpoint(step Step) {
 	d.mux.Lock()
 	defer d.mux.Unlock()
 	d.Breakpoints[step] = struct{}{}
 }
 
-// RemoveBreakpoint removes a breakpoint from a task by its name.
+// RemoveBreakpoint removes a breakpoint.
 func (d *Debugger) RemoveBreakpoint(breakpoint Step) {
 	d.mux.Lock()
 	defer d.mux.Unlock()
 	delete(d.Breakpoints, breakpoint)
 }
 
-// BeforeTaskExecution is modified to pause at breakpoints or in step mode, waiting for channel input to continue.
-func (d *Debugger) BeforeTaskExecution(step Step, request interface{}) {
-	fmt.Printf("Before executing task %s: request = %+v\n", step, request)
-	hasBreakpoint := false
+// HasBreakpoint returns true if any breakpoint matches supplied step
+func (d *Debugger) HasBreakpoint(step Step) bool {
 	d.mux.Lock()
-	_, hasBreakpoint = d.Breakpoints[step]
-	d.mux.Unlock()
-
-	if hasBreakpoint || d.StepMode {
-		fmt.Println("Execution paused. Press enter to continue...")
-		go func() {
-			fmt.Scanln()           // Wait for user input
-			d.continueExec <- true // Signal to continue execution
-		}()
-		<-d.continueExec // Wait for signal to continue
-	}
+	defer d.mux.Unlock()
+	return d.hasBreakpoint(step)
 }
 
-// AfterTaskExecution logs task results, similar to the previous implementation.
-func (d *Debugger) AfterTaskExecution(step Step, result interface{}) {
-	fmt.Printf("After executing task %s: result = %+v\n", step, result)
-	if d.StepMode {
-		// In step mode, pause after each task execution.
-		fmt.Println("Step execution paused. Press enter to continue to the next task...")
-		go func() {
-			fmt.Scanln()           // Wait for user input
-			d.continueExec <- true // Signal to continue execution
-		}()
-		<-d.continueExec // Wait for signal to continue
+func (d *Debugger) hasBreakpoint(step Step) bool {
+	for breakpoint := range d.Breakpoints {
+		if breakpoint.Matches(step) {
+			return true
+		}
 	}
+	return false
 }
 
 // EnableStepMode enables or disables step mode.
 func (d *Debugger) EnableStepMode(enable bool) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
 	d.StepMode = enable
 }
+
+// Next pauses execution at the next action
+func (d *Debugger) Next() {
+	d.EnableStepMode(true)
+}
+
+// Continue resumes execution till the next breakpoint
+func (d *Debugger) Continue() {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.StepMode = false
+	d.paused = make(map[Step]int)
+}
+
+// Detach disables debugger, remaining actions run without pausing
+func (d *Debugger) Detach() {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.disabled = true
+}
+
+// pauseBefore returns true if execution should pause before supplied step, paused step is tracked till After
+func (d *Debugger) pauseBefore(step Step) bool {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if d.disabled || !(d.StepMode || d.hasBreakpoint(step)) {
+		return false
+	}
+	if d.paused == nil {
+		d.paused = make(map[Step]int)
+	}
+	d.paused[step]++
+	return true
+}
+
+// pauseAfter returns true if execution paused before supplied step
+func (d *Debugger) pauseAfter(step Step) bool {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	return !d.disabled && d.paused[step] > 0
+}
+
+// release removes supplied step pause once it is not re-run
+func (d *Debugger) release(step Step) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if d.paused[step] > 0 {
+		if d.paused[step]--; d.paused[step] == 0 {
+			delete(d.paused, step)
+		}
+	}
+}
+
+// Before pauses before action execution at breakpoints or in step mode, it returns Skip if action should be skipped
+func (d *Debugger) Before(step Step, request interface{}, state data.Map) Decision {
+	if !d.pauseBefore(step) {
+		return Proceed
+	}
+	d.stop.Lock()
+	defer d.stop.Unlock()
+	if d.Controller != nil {
+		return d.Controller.Paused(&Stop{Step: step, Before: true, Request: request, State: state})
+	}
+	d.printf("paused before %v\n", step)
+	return d.prompt(step, request, nil, nil, state, true)
+}
+
+// After pauses after action paused before, it returns Rerun if action should be executed again
+func (d *Debugger) After(step Step, request, response interface{}, err error, state data.Map) (decision Decision) {
+	if !d.pauseAfter(step) {
+		return Proceed
+	}
+	defer func() {
+		if decision != Rerun {
+			d.release(step)
+		}
+	}()
+	d.stop.Lock()
+	defer d.stop.Unlock()
+	if d.Controller != nil {
+		return d.Controller.Paused(&Stop{Step: step, Request: request, Response: response, Error: err, State: state})
+	}
+	if err != nil {
+		d.printf("failed %v: %v\n", step, err)
+	} else {
+		d.printf("executed %v\n", step)
+	}
+	return d.prompt(step, request, response, err, state, false)
+}
+
+func (d *Debugger) prompt(step Step, request, response interface{}, err error, state data.Map, before bool) Decision {
+	for {
+		d.printf("(debug) ")
+		line, ok := d.readLine()
+		if !ok {
+			d.Detach()
+			return Proceed
+		}
+		command, args := line, ""
+		if index := strings.Index(line, " "); index != -1 {
+			command, args = line[:index], strings.TrimSpace(line[index+1:])
+		}
+		switch command {
+		case "", "n", "next":
+			d.Next()
+			return Proceed
+		case "c", "continue":
+			d.Continue()
+			return Proceed
+		case "s", "skip":
+			if before {
+				return Skip
+			}
+			d.printf("action has been already executed\n")
+		case "r", "rerun":
+			if !before {
+				return Rerun
+			}
+			d.printf("action has not been executed yet\n")
+		case "p", "print":
+			d.printState(state, args)
+		case "set":
+			d.setState(state, args)
+		case "e", "eval":
+			d.printValue(state.Expand(args))
+		case "req":
+			d.printValue(request)
+		case "out":
+			if err != nil {
+				d.printf("%v\n", err)
+			} else {
+				d.printValue(response)
+			}
+		case "b", "break":
+			d.SetBreakpoint(ParseStep(args))
+		case "d", "delete":
+			d.RemoveBreakpoint(ParseStep(args))
+		case "l", "list":
+			d.listBreakpoints()
+		case "q", "quit":
+			d.Detach()
+			return Proceed
+		default:
+			d.printf(debuggerHelp)
+		}
+	}
+}
+
+func (d *Debugger) readLine() (string, bool) {
+	if d.scanner == nil {
+		d.scanner = bufio.NewScanner(d.In)
+	}
+	if !d.scanner.Scan() {
+		return "", false
+	}
+	return strings.TrimSpace(d.scanner.Text()), true
+}
+
+func (d *Debugger) printState(state data.Map, key string) {
+	if key == "" {
+		var keys = make([]string, 0)
+		for k, v := range state {
+			if toolbox.IsFunc(v) {
+				continue
+			}
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		d.printf("%v\n", strings.Join(keys, ", "))
+		return
+	}
+	value, ok := state.GetValue(key)
+	if !ok {
+		d.printf("%v: undefined\n", key)
+		return
+	}
+	d.printValue(value)
+}
+
+func (d *Debugger) setState(state data.Map, assignment string) {
+	index := strings.Index(assignment, "=")
+	if index == -1 {
+		d.printf("expected key=value\n")
+		return
+	}
+	key := strings.TrimSpace(assignment[:index])
+	text := strings.TrimSpace(assignment[index+1:])
+	var value interface{} = text
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(text), &decoded); err == nil {
+		value = decoded
+	}
+	state.SetValue(key, value)
+}
+
+func (d *Debugger) printValue(value interface{}) {
+	if toolbox.IsMap(value) {
+		aMap := data.Map(toolbox.AsMap(value))
+		value = aMap.AsEncodableMap()
+	}
+	if text, err := toolbox.AsIndentJSONText(value); err == nil {
+		d.printf("%v\n", text)
+		return
+	}
+	d.printf("%v\n", value)
+}
+
+func (d *Debugger) listBreakpoints() {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	for breakpoint := range d.Breakpoints {
+		d.printf("%v\n", breakpoint)
+	}
+}
+
+func (d *Debugger) printf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(d.Out, format, args...)
+}
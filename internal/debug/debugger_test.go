@@ -0,0 +1,161 @@
+package debug
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox/data"
+)
+
+func TestParseStep(t *testing.T) {
+	var useCases = []struct {
+		description string
+		selector    string
+		expect      Step
+	}{
+		{
+			description: "full selector",
+			selector:    "app/build/checkout",
+			expect:      Step{Workflow: "app", TaskName: "build", Action: "checkout"},
+		},
+		{
+			description: "task and action selector",
+			selector:    "build/checkout",
+			expect:      Step{Workflow: "*", TaskName: "build", Action: "checkout"},
+		},
+		{
+			description: "tag ID selector",
+			selector:    "#test1",
+			expect:      Step{TagID: "test1"},
+		},
+	}
+	for _, useCase := range useCases {
+		assert.EqualValues(t, useCase.expect, ParseStep(useCase.selector), useCase.description)
+	}
+}
+
+func TestStep_Matches(t *testing.T) {
+	step := Step{Workflow: "app", TaskName: "build", Action: "checkout", TagID: "build_checkout"}
+	assert.True(t, ParseStep("app/build/checkout").Matches(step))
+	assert.True(t, ParseStep("build/*").Matches(step))
+	assert.True(t, ParseStep("#build_checkout").Matches(step))
+	assert.False(t, ParseStep("test/*").Matches(step))
+	assert.False(t, ParseStep("#test1").Matches(step))
+}
+
+func TestDebugger(t *testing.T) {
+	var useCases = []struct {
+		description    string
+		input          string
+		stepMode       bool
+		breakpoint     string
+		expectBefore   Decision
+		expectAfter    Decision
+		expectState    map[string]interface{}
+		expectedOutput string
+	}{
+		{
+			description:  "no breakpoint match",
+			breakpoint:   "test/*",
+			input:        "s\n",
+			expectBefore: Proceed,
+			expectAfter:  Proceed,
+		},
+		{
+			description:    "skip at breakpoint",
+			breakpoint:     "build/*",
+			input:          "p\ns\n",
+			expectBefore:   Skip,
+			expectAfter:    Proceed,
+			expectedOutput: "paused before */build/checkout",
+		},
+		{
+			description:  "set state and re-run",
+			stepMode:     true,
+			input:        "set counter=3\nn\nr\n",
+			expectBefore: Proceed,
+			expectAfter:  Rerun,
+			expectState:  map[string]interface{}{"counter": float64(3)},
+		},
+		{
+			description:    "evaluate expression",
+			stepMode:       true,
+			input:          "e $name-1\nc\nc\n",
+			expectBefore:   Proceed,
+			expectAfter:    Proceed,
+			expectedOutput: "endly-1",
+		},
+	}
+
+	for _, useCase := range useCases {
+		output := new(bytes.Buffer)
+		debugger := NewDebugger()
+		debugger.In = strings.NewReader(useCase.input)
+		debugger.Out = output
+		debugger.EnableStepMode(useCase.stepMode)
+		if useCase.breakpoint != "" {
+			debugger.SetBreakpoint(ParseStep(useCase.breakpoint))
+		}
+		state := data.NewMap()
+		state.Put("name", "endly")
+		step := Step{TaskName: "build", Action: "checkout"}
+		assert.Equal(t, useCase.expectBefore, debugger.Before(step, nil, state), useCase.description)
+		assert.Equal(t, useCase.expectAfter, debugger.After(step, nil, nil, nil, state), useCase.description)
+		for k, v := range useCase.expectState {
+			assert.EqualValues(t, v, state.Get(k), useCase.description)
+		}
+		assert.Contains(t, output.String(), useCase.expectedOutput, useCase.description)
+	}
+}
+
+// countingController counts paused steps
+type countingController struct {
+	mux     sync.Mutex
+	active  int
+	stops   int
+	overlap bool
+}
+
+func (c *countingController) Paused(stop *Stop) Decision {
+	c.mux.Lock()
+	c.active++
+	c.stops++
+	c.overlap = c.overlap || c.active > 1
+	c.mux.Unlock()
+	time.Sleep(time.Millisecond)
+	c.mux.Lock()
+	c.active--
+	c.mux.Unlock()
+	return Proceed
+}
+
+func TestDebugger_Concurrent(t *testing.T) {
+	debugger := NewDebugger()
+	controller := &countingController{}
+	debugger.Controller = controller
+	debugger.EnableStepMode(true)
+	state := data.NewMap()
+	group := &sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+			step := Step{Workflow: "app", TaskName: "build", Action: "compile"}
+			if debugger.Before(step, nil, state) == Proceed {
+				debugger.After(step, nil, nil, nil, state)
+			}
+		}()
+	}
+	group.Wait()
+	assert.False(t, controller.overlap, "paused steps should be handled one at a time")
+	assert.Equal(t, 16, controller.stops)
+	assert.Equal(t, 0, len(debugger.paused))
+
+	debugger.Continue()
+	assert.Equal(t, Proceed, debugger.After(Step{Action: "compile"}, nil, nil, nil, state))
+	assert.Equal(t, 16, controller.stops)
+}
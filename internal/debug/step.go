@@ -1,6 +1,60 @@
 package debug
 
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	wildcard     = "*"
+	tagIDPrefix  = "#"
+	stepSplitter = "/"
+)
+
+// Step represents workflow action step or breakpoint selector, empty or '*' selector field matches any value
 type Step struct {
 	Workflow string
 	TaskName string
+	Action   string
+	TagID    string
+}
+
+// Matches returns true if supplied step matches this selector
+func (s Step) Matches(step Step) bool {
+	return matches(s.Workflow, step.Workflow) &&
+		matches(s.TaskName, step.TaskName) &&
+		matches(s.Action, step.Action) &&
+		matches(s.TagID, step.TagID)
+}
+
+// String returns step selector
+func (s Step) String() string {
+	if s.TagID != "" && s.Workflow == "" && s.TaskName == "" && s.Action == "" {
+		return tagIDPrefix + s.TagID
+	}
+	return fmt.Sprintf("%v/%v/%v", orWildcard(s.Workflow), orWildcard(s.TaskName), orWildcard(s.Action))
+}
+
+// ParseStep parses breakpoint selector: #tagID or workflow/task/action, where leading elements can be omitted i.e task/action
+func ParseStep(selector string) Step {
+	selector = strings.TrimSpace(selector)
+	if strings.HasPrefix(selector, tagIDPrefix) {
+		return Step{TagID: selector[1:]}
+	}
+	var parts = strings.Split(selector, stepSplitter)
+	for len(parts) < 3 {
+		parts = append([]string{wildcard}, parts...)
+	}
+	return Step{Workflow: parts[0], TaskName: parts[1], Action: strings.Join(parts[2:], stepSplitter)}
+}
+
+func matches(selector, value string) bool {
+	return selector == "" || selector == wildcard || selector == value
+}
+
+func orWildcard(value string) string {
+	if value == "" {
+		return wildcard
+	}
+	return value
 }
@@ -0,0 +1,52 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/viant/afs/url"
+)
+
+// Severity represents issue severity
+type Severity string
+
+const (
+	//SeverityError represents issue that fails workflow at run time
+	SeverityError = Severity("error")
+	//SeverityWarning represents issue that may fail workflow at run time
+	SeverityWarning = Severity("warning")
+)
+
+// Issue represents workflow lint issue
+type Issue struct {
+	URL      string
+	Line     int
+	Column   int
+	Severity Severity
+	Node     string
+	Message  string
+}
+
+// String returns issue formatted as file:line:column: severity: node: message
+func (i *Issue) String() string {
+	location := url.Path(i.URL)
+	if i.Line > 0 {
+		location = fmt.Sprintf("%v:%d:%d", location, i.Line, i.Column)
+	}
+	if i.Node == "" {
+		return fmt.Sprintf("%v: %v: %v", location, i.Severity, i.Message)
+	}
+	return fmt.Sprintf("%v: %v: %v: %v", location, i.Severity, i.Node, i.Message)
+}
+
+// Issues represents lint issues
+type Issues []*Issue
+
+// HasErrors returns true if any issue has error severity
+func (i Issues) HasErrors() bool {
+	for _, issue := range i {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,417 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/graph"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+)
+
+const (
+	workflowService       = "workflow"
+	maxSuggestionDistance = 2
+)
+
+// runtimeKeys represents state keys published by workflow service at run time
+var runtimeKeys = []string{"params", "data", "self", "tasks", "error", "errorJSON", "index", "Data", model.OwnerURL}
+
+var variableExpr = regexp.MustCompile(`\$\{?([A-Za-z_][A-Za-z0-9_]*)(\()?`)
+
+// Linter statically validates workflow service actions, request attributes, goto targets and variables
+type Linter struct {
+	manager endly.Manager
+	graph   *graph.Service
+}
+
+// session represents a single workflow linting session
+type session struct {
+	workflow  *model.Workflow
+	URL       string
+	positions []*graph.Position
+	defined   map[string]bool
+	udfs      data.Map
+	reported  map[string]bool
+	issues    Issues
+}
+
+// Lint validates supplied workflow, params are run request parameters
+func (l *Linter) Lint(ctx context.Context, workflow *model.Workflow, params map[string]interface{}) (Issues, error) {
+	if workflow == nil {
+		return nil, fmt.Errorf("workflow was empty")
+	}
+	aSession := &session{
+		workflow: workflow,
+		defined:  make(map[string]bool),
+		reported: make(map[string]bool),
+		issues:   make(Issues, 0),
+	}
+	if workflow.Source != nil {
+		aSession.URL = workflow.Source.URL
+		if node, err := l.graph.LoadWorkflow(ctx, aSession.URL); err == nil {
+			aSession.positions = node.Positions()
+		}
+	}
+	l.defineBuiltins(aSession, params)
+	aSession.defineNode(workflow.AbstractNode)
+	if workflow.TasksNode != nil {
+		aSession.defineTasks(workflow.TasksNode)
+	}
+	aSession.checkVariables(nil, "", workflow.AbstractNode.Init)
+	if workflow.TasksNode != nil {
+		l.lintTasks(aSession, workflow.TasksNode)
+	}
+	return aSession.issues, nil
+}
+
+func (l *Linter) defineBuiltins(aSession *session, params map[string]interface{}) {
+	state := l.manager.NewContext(nil).State()
+	for k := range state {
+		aSession.defined[k] = true
+	}
+	if udfs, ok := state.GetValue(data.UDFKey); ok {
+		aSession.udfs = data.Map(toolbox.AsMap(udfs))
+	}
+	for _, key := range runtimeKeys {
+		aSession.defined[key] = true
+	}
+	for k := range params {
+		aSession.defined[k] = true
+	}
+	aSession.defined[aSession.workflow.Name] = true
+}
+
+func (l *Linter) lintTasks(aSession *session, tasks *model.TasksNode) {
+	for _, task := range tasks.Tasks {
+		position := aSession.position(graph.TypeTask, task.Name)
+		if task.AbstractNode != nil {
+			aSession.checkVariables(position, task.Name, task.Init)
+			aSession.checkVariables(position, task.Name, task.When)
+		}
+		for _, action := range task.Actions {
+			l.lintAction(aSession, task, action)
+		}
+		if task.TasksNode != nil {
+			l.lintTasks(aSession, task.TasksNode)
+		}
+	}
+}
+
+func (l *Linter) lintAction(aSession *session, task *model.Task, action *model.Action) {
+	name := action.Name
+	if name == "" {
+		name = task.Name
+	}
+	position := aSession.position(graph.TypeAction, name)
+	if action.ServiceRequest == nil {
+		return
+	}
+	selector := action.Service + ":" + action.Action
+	if !strings.Contains(selector, "$") {
+		if route := l.resolve(aSession, position, name, action.Service, action.Action); route != nil {
+			l.checkRequest(aSession, position, name, selector, route, action.Request)
+		}
+	}
+	if action.Service == workflowService {
+		l.checkTargets(aSession, position, name, action.Action, action.Request)
+	}
+	aSession.checkVariables(position, name, action.Request)
+	if action.AbstractNode != nil {
+		aSession.checkVariables(position, name, action.Init)
+		aSession.checkVariables(position, name, action.When)
+	}
+	aSession.checkVariables(position, name, action.Skip)
+}
+
+// resolve returns route for supplied service action or reports unknown service or action
+func (l *Linter) resolve(aSession *session, position *graph.Position, node, serviceID, action string) *endly.Route {
+	service, err := l.manager.Service(serviceID)
+	if err != nil {
+		message := fmt.Sprintf("unknown service: %v", serviceID)
+		aSession.report(position, node, SeverityError, withSuggestion(message, serviceID, l.serviceIDs()))
+		return nil
+	}
+	route, err := service.Route(action)
+	if err != nil {
+		message := fmt.Sprintf("unknown action: %v:%v", serviceID, action)
+		aSession.report(position, node, SeverityError, withSuggestion(message, action, service.Actions()))
+		return nil
+	}
+	return route
+}
+
+func (l *Linter) serviceIDs() []string {
+	var result = make([]string, 0)
+	for id := range endly.Services(l.manager) {
+		result = append(result, id)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// checkRequest checks request attributes against service action request struct fields
+func (l *Linter) checkRequest(aSession *session, position *graph.Position, node, selector string, route *endly.Route, request interface{}) {
+	if route.RequestProvider == nil || route.OnRawRequest != nil || request == nil || !toolbox.IsMap(request) {
+		return
+	}
+	requestType := reflect.TypeOf(route.RequestProvider())
+	for requestType.Kind() == reflect.Ptr {
+		requestType = requestType.Elem()
+	}
+	if requestType.Kind() != reflect.Struct || requestType.NumField() == 0 { //no field request accepts any attributes, i.e. nop
+		return
+	}
+	var fields = make(map[string]bool)
+	collectFields(requestType, fields)
+	var candidates = make([]string, 0, len(fields))
+	for field := range fields {
+		candidates = append(candidates, field)
+	}
+	sort.Strings(candidates)
+	var keys = make([]string, 0)
+	for k := range toolbox.AsMap(request) {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if strings.HasPrefix(key, "@") || strings.Contains(key, "$") || fields[normalizeKey(key)] {
+			continue
+		}
+		message := fmt.Sprintf("unknown %v request attribute: %v (%v)", selector, key, requestType.Name())
+		aSession.report(position, node, SeverityError, withSuggestion(message, normalizeKey(key), candidates))
+	}
+}
+
+// checkTargets checks goto and switch task targets
+func (l *Linter) checkTargets(aSession *session, position *graph.Position, node, action string, request interface{}) {
+	if !toolbox.IsMap(request) {
+		return
+	}
+	var targets = make([]string, 0)
+	aMap := toolbox.AsMap(request)
+	switch action {
+	case "goto":
+		targets = append(targets, toolbox.AsString(lookup(aMap, "task")))
+	case "switch":
+		if cases := lookup(aMap, "cases"); cases != nil && toolbox.IsSlice(cases) {
+			for _, item := range toolbox.AsSlice(cases) {
+				if toolbox.IsMap(item) {
+					targets = append(targets, toolbox.AsString(lookup(toolbox.AsMap(item), "task")))
+				}
+			}
+		}
+		if defaultCase := lookup(aMap, "default"); defaultCase != nil && toolbox.IsMap(defaultCase) {
+			targets = append(targets, toolbox.AsString(lookup(toolbox.AsMap(defaultCase), "task")))
+		}
+	}
+	for _, target := range targets {
+		if target == "" || strings.Contains(target, "$") || aSession.workflow.TasksNode.Has(target) {
+			continue
+		}
+		aSession.report(position, node, SeverityError, fmt.Sprintf("unreachable %v target task: %v", action, target))
+	}
+}
+
+func (s *session) defineTasks(tasks *model.TasksNode) {
+	for _, task := range tasks.Tasks {
+		s.defineNode(task.AbstractNode)
+		for _, action := range task.Actions {
+			s.defineNode(action.AbstractNode)
+			s.defined[action.Name] = true
+			if action.ServiceRequest != nil {
+				s.defined[action.Action] = true
+			}
+			if action.Repeater != nil {
+				for _, extract := range action.Extract {
+					s.defined[rootName(extract.Key)] = true
+				}
+				s.defineVariables(action.Variables)
+			}
+		}
+		if task.TasksNode != nil {
+			s.defineTasks(task.TasksNode)
+		}
+	}
+}
+
+func (s *session) defineNode(node *model.AbstractNode) {
+	if node == nil {
+		return
+	}
+	s.defineVariables(node.Init)
+	s.defineVariables(node.Post)
+}
+
+func (s *session) defineVariables(variables model.Variables) {
+	for _, variable := range variables {
+		if variable != nil {
+			s.defined[rootName(variable.Name)] = true
+		}
+	}
+}
+
+// checkVariables reports variables and functions referenced in supplied value which are not defined
+func (s *session) checkVariables(position *graph.Position, node string, value interface{}) {
+	switch actual := value.(type) {
+	case nil:
+	case string:
+		for _, match := range variableExpr.FindAllStringSubmatchIndex(actual, -1) {
+			if match[0] > 0 && actual[match[0]-1] == '$' {
+				continue
+			}
+			name := actual[match[2]:match[3]]
+			isFunction := match[4] != -1
+			if isFunction {
+				if s.udfs.Has(name) || s.defined[name] {
+					continue
+				}
+				s.report(position, node, SeverityWarning, fmt.Sprintf("undefined function: $%v()", name))
+				continue
+			}
+			if s.defined[name] || s.udfs.Has(name) {
+				continue
+			}
+			s.report(position, node, SeverityWarning, fmt.Sprintf("unresolved variable: $%v", name))
+		}
+	case model.Variables:
+		for _, variable := range actual {
+			if variable != nil {
+				s.checkVariables(position, node, variable.Value)
+				s.checkVariables(position, node, variable.From)
+			}
+		}
+	default:
+		switch {
+		case toolbox.IsMap(value):
+			for k, v := range toolbox.AsMap(value) {
+				s.checkVariables(position, node, k)
+				s.checkVariables(position, node, v)
+			}
+		case toolbox.IsSlice(value):
+			for _, item := range toolbox.AsSlice(value) {
+				s.checkVariables(position, node, item)
+			}
+		}
+	}
+}
+
+// position returns first matching pipeline position
+func (s *session) position(nodeType graph.Type, name string) *graph.Position {
+	for _, position := range s.positions {
+		if position.Type == nodeType && position.Name == name {
+			return position
+		}
+	}
+	return nil
+}
+
+func (s *session) report(position *graph.Position, node string, severity Severity, message string) {
+	key := node + "/" + message
+	if s.reported[key] {
+		return
+	}
+	s.reported[key] = true
+	issue := &Issue{URL: s.URL, Node: node, Severity: severity, Message: message}
+	if position != nil {
+		issue.Line = position.Line
+		issue.Column = position.Column
+	}
+	s.issues = append(s.issues, issue)
+}
+
+// collectFields collects normalized field names, including embedded struct fields
+func collectFields(structType reflect.Type, fields map[string]bool) {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous {
+			fields[normalizeKey(fieldType.Name())] = true
+			if fieldType.Kind() == reflect.Struct {
+				collectFields(fieldType, fields)
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		fields[normalizeKey(field.Name)] = true
+		for _, tag := range []string{"json", "yaml"} {
+			if name := strings.Split(field.Tag.Get(tag), ",")[0]; name != "" && name != "-" {
+				fields[normalizeKey(name)] = true
+			}
+		}
+	}
+}
+
+func normalizeKey(key string) string {
+	key = strings.ToLower(key)
+	key = strings.Replace(key, "_", "", -1)
+	return strings.Replace(key, "-", "", -1)
+}
+
+// rootName returns state root key for supplied variable name
+func rootName(name string) string {
+	name = strings.TrimSpace(name)
+	name = strings.TrimLeft(name, "!->$")
+	if index := strings.IndexAny(name, ".[{"); index != -1 {
+		name = name[:index]
+	}
+	return name
+}
+
+func lookup(aMap map[string]interface{}, key string) interface{} {
+	for k, v := range aMap {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return nil
+}
+
+func withSuggestion(message, value string, candidates []string) string {
+	best, bestDistance := "", maxSuggestionDistance+1
+	for _, candidate := range candidates {
+		if distance := levenshtein(strings.ToLower(value), strings.ToLower(candidate)); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	if best == "" {
+		return message
+	}
+	return fmt.Sprintf("%v, did you mean: %v?", message, best)
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+// New creates a new workflow linter
+func New(manager endly.Manager) *Linter {
+	return &Linter{manager: manager, graph: graph.New()}
+}
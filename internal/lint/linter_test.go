@@ -0,0 +1,43 @@
+package lint
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/afs/file"
+	"github.com/viant/afs/url"
+	"github.com/viant/endly"
+	"github.com/viant/endly/service/workflow"
+)
+
+func TestLinter_Lint(t *testing.T) {
+	location, _ := filepath.Abs(filepath.Join("testdata", "app.yaml"))
+	URL := url.Normalize(location, file.Scheme)
+	request, err := workflow.NewRunRequestFromURL(URL)
+	if !assert.Nil(t, err) {
+		return
+	}
+	baseURL, _ := url.Split(URL, file.Scheme)
+	aWorkflow, err := request.AsWorkflow("app", baseURL)
+	if !assert.Nil(t, err) {
+		return
+	}
+	linter := New(endly.New())
+	issues, err := linter.Lint(context.Background(), aWorkflow, nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	var actual = make([]string, 0)
+	for _, issue := range issues {
+		actual = append(actual, issue.String())
+	}
+	assert.EqualValues(t, []string{
+		location + ":5:3: error: build: unknown workflow:print request attribute: colour (PrintRequest)",
+		location + ":5:3: warning: build: unresolved variable: $version",
+		location + ":9:3: error: check: unknown action: workflow:prnt, did you mean: print?",
+		location + ":12:3: error: jump: unreachable goto target task: deploy",
+	}, actual)
+	assert.True(t, issues.HasErrors())
+}
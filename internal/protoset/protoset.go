@@ -0,0 +1,176 @@
+package protoset
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/desc/protoparse"
+	"github.com/viant/toolbox"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Load returns descriptors registry for supplied .proto files and descriptor sets (protoc --descriptor_set_out --include_imports)
+func Load(protoFiles, importPaths, descriptorSets []string) (*protoregistry.Files, error) {
+	var set = &descriptorpb.FileDescriptorSet{}
+	if len(protoFiles) > 0 {
+		parsed, err := parse(protoFiles, importPaths)
+		if err != nil {
+			return nil, err
+		}
+		set.File = append(set.File, parsed.File...)
+	}
+	for _, location := range descriptorSets {
+		content, err := ioutil.ReadFile(location)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read descriptor set %v, %w", location, err)
+		}
+		var descriptorSet = &descriptorpb.FileDescriptorSet{}
+		if err = proto.Unmarshal(content, descriptorSet); err != nil {
+			return nil, fmt.Errorf("failed to decode descriptor set %v, %w", location, err)
+		}
+		set.File = append(set.File, descriptorSet.File...)
+	}
+	return NewFiles(set.File)
+}
+
+// NewFiles returns descriptors registry for supplied file descriptors, duplicated files are ignored
+func NewFiles(files []*descriptorpb.FileDescriptorProto) (*protoregistry.Files, error) {
+	var unique = make([]*descriptorpb.FileDescriptorProto, 0, len(files))
+	var names = make(map[string]bool)
+	for _, file := range files {
+		if names[file.GetName()] {
+			continue
+		}
+		names[file.GetName()] = true
+		unique = append(unique, file)
+	}
+	result, err := protodesc.NewFiles(&descriptorpb.FileDescriptorSet{File: unique})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build descriptors, %w", err)
+	}
+	return result, nil
+}
+
+func parse(protoFiles, importPaths []string) (*descriptorpb.FileDescriptorSet, error) {
+	var parser = protoparse.Parser{ImportPaths: append([]string{}, importPaths...)}
+	var names = make([]string, 0, len(protoFiles))
+	for _, protoFile := range protoFiles {
+		name := ""
+		for _, importPath := range importPaths {
+			if relative, err := filepath.Rel(importPath, protoFile); err == nil && !strings.HasPrefix(relative, "..") {
+				name = relative
+				break
+			}
+		}
+		if name == "" {
+			parser.ImportPaths = append(parser.ImportPaths, path.Dir(protoFile))
+			name = path.Base(protoFile)
+		}
+		names = append(names, name)
+	}
+	descriptors, err := parser.ParseFiles(names...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %v, %w", strings.Join(protoFiles, ","), err)
+	}
+	return desc.ToFileDescriptorSet(descriptors...), nil
+}
+
+// ParseMethod returns service full name and method name for supplied method, i.e. /pkg.Service/Method, pkg.Service/Method or pkg.Service.Method
+func ParseMethod(method string) (string, string, error) {
+	name := strings.Replace(strings.TrimPrefix(method, "/"), "/", ".", 1)
+	index := strings.LastIndex(name, ".")
+	if index == -1 {
+		return "", "", fmt.Errorf("invalid method name: %v, expected pkg.Service/Method", method)
+	}
+	return name[:index], name[index+1:], nil
+}
+
+// FindMethod returns method descriptor for supplied method name (see ParseMethod)
+func FindMethod(files *protoregistry.Files, method string) (protoreflect.MethodDescriptor, error) {
+	serviceName, methodName, err := ParseMethod(method)
+	if err != nil {
+		return nil, err
+	}
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lookup service %v, %w", serviceName, err)
+	}
+	service, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%v is not a service", serviceName)
+	}
+	result := service.Methods().ByName(protoreflect.Name(methodName))
+	if result == nil {
+		return nil, fmt.Errorf("method %v not found in service %v", methodName, serviceName)
+	}
+	return result, nil
+}
+
+// Services returns all services descriptors
+func Services(files *protoregistry.Files) []protoreflect.ServiceDescriptor {
+	var result = make([]protoreflect.ServiceDescriptor, 0)
+	files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		services := file.Services()
+		for i := 0; i < services.Len(); i++ {
+			result = append(result, services.Get(i))
+		}
+		return true
+	})
+	return result
+}
+
+// FullMethod returns method gRPC name, i.e. /pkg.Service/Method
+func FullMethod(method protoreflect.MethodDescriptor) string {
+	return fmt.Sprintf("/%v/%v", method.Parent().FullName(), method.Name())
+}
+
+// NewMessage returns dynamic message for supplied value, value can be JSON text or map
+func NewMessage(descriptor protoreflect.MessageDescriptor, value interface{}) (*dynamicpb.Message, error) {
+	result := dynamicpb.NewMessage(descriptor)
+	var content []byte
+	switch actual := value.(type) {
+	case nil:
+		return result, nil
+	case string:
+		content = []byte(actual)
+	case []byte:
+		content = actual
+	default:
+		normalized, err := toolbox.NormalizeKVPairs(actual)
+		if err != nil {
+			return nil, err
+		}
+		if content, err = json.Marshal(normalized); err != nil {
+			return nil, err
+		}
+	}
+	if len(content) == 0 {
+		return result, nil
+	}
+	if err := protojson.Unmarshal(content, result); err != nil {
+		return nil, fmt.Errorf("failed to convert to %v, %w", descriptor.FullName(), err)
+	}
+	return result, nil
+}
+
+// AsMap returns message as map, fields use proto JSON names
+func AsMap(message proto.Message) (map[string]interface{}, error) {
+	content, err := protojson.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	var result = make(map[string]interface{})
+	err = json.Unmarshal(content, &result)
+	return result, err
+}
@@ -10,6 +10,7 @@ type Action struct {
 	*Repeater       `yaml:",inline"`
 	Async           bool   `description:"flag to run action async" yaml:",omitempty"`
 	Skip            string `description:"criteria to skip current TagID"  yaml:",omitempty"`
+	Retry           *Retry `description:"optional retry on error policy" yaml:",omitempty"`
 	skipEvan        eval.Compute
 }
 
@@ -44,6 +45,14 @@ func (a *Action) Init() error {
 	if err := a.Validate(); err != nil {
 		return err
 	}
+	if a.Retry != nil {
+		if err := a.Retry.Init(); err != nil {
+			return err
+		}
+		if err := a.Retry.Validate(); err != nil {
+			return err
+		}
+	}
 
 	a.initSleepTime()
 	return nil
@@ -65,6 +74,11 @@ func (a *Action) Clone() *Action {
 	serviceRequest := *a.ServiceRequest
 	metaTag := *a.MetaTag
 	repeater := *a.Repeater
+	var retry *Retry
+	if a.Retry != nil {
+		retryCopy := *a.Retry
+		retry = &retryCopy
+	}
 	return &Action{
 		AbstractNode:   &abstract,
 		ServiceRequest: &serviceRequest,
@@ -72,6 +86,7 @@ func (a *Action) Clone() *Action {
 		Repeater:       &repeater,
 		Async:          a.Async,
 		Skip:           a.Skip,
+		Retry:          retry,
 	}
 }
 
@@ -22,6 +22,7 @@ type Activity struct {
 	Response        map[string]interface{}
 	ServiceResponse *endly.ServiceResponse
 	Logging         *bool
+	Attempt         int `json:",omitempty"`
 }
 
 
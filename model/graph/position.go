@@ -0,0 +1,104 @@
+package graph
+
+import (
+	"github.com/viant/endly/model/graph/yml"
+	"gopkg.in/yaml.v3"
+)
+
+// Position represents pipeline task or action source position
+type Position struct {
+	Type   Type
+	Name   string
+	Task   string //parent task name
+	Line   int
+	Column int
+}
+
+// Positions returns pipeline tasks and actions positions in document order
+func (n *Node) Positions() []*Position {
+	if n.Type != TypeWorkflow {
+		return nil
+	}
+	pipeline := lookupKey(n.Node, "pipeline")
+	if pipeline == nil {
+		return nil
+	}
+	var result = make([]*Position, 0)
+	collectPositions(pipeline, "", &result)
+	return result
+}
+
+// ActionAt returns action declared at or enclosing supplied line, for a task line the first task action is returned
+func ActionAt(positions []*Position, line int) *Position {
+	index := -1
+	for i, position := range positions {
+		if position.Line > line {
+			break
+		}
+		index = i
+	}
+	if index == -1 {
+		return nil
+	}
+	for _, position := range positions[index:] {
+		if position.Type == TypeAction {
+			return position
+		}
+	}
+	return nil
+}
+
+func collectPositions(node *yml.Node, task string, result *[]*Position) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], (*yml.Node)(node.Content[i+1])
+		if value.Kind != yaml.MappingNode {
+			continue
+		}
+		position := &Position{Type: TypeTask, Name: key.Value, Task: task, Line: key.Line, Column: key.Column}
+		if isActionNode(value) {
+			position.Type = TypeAction
+			*result = append(*result, position)
+			continue
+		}
+		if !hasActionNode(value) {
+			continue
+		}
+		*result = append(*result, position)
+		collectPositions(value, key.Value, result)
+	}
+}
+
+func isActionNode(node *yml.Node) bool {
+	return lookupKey(node, "action") != nil || lookupKey(node, "workflow") != nil
+}
+
+func hasActionNode(node *yml.Node) bool {
+	if node.Kind != yaml.MappingNode {
+		return false
+	}
+	if isActionNode(node) {
+		return true
+	}
+	for i := 1; i < len(node.Content); i += 2 {
+		if hasActionNode((*yml.Node)(node.Content[i])) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupKey returns mapping value node for supplied key
+func lookupKey(node *yml.Node, key string) *yml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return (*yml.Node)(node.Content[i+1])
+		}
+	}
+	return nil
+}
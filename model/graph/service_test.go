@@ -51,3 +51,39 @@ func TestService_LoadWorkflow(t *testing.T) {
 	}
 	assert.Equal(t, []string{"checkSkip", "test"}, templateTask)
 }
+
+func TestNode_Positions(t *testing.T) {
+	srv := New()
+	URL := "embed:///testdata/appx_regression.yaml"
+	workflow, err := srv.LoadWorkflow(context.Background(), URL, &embeddFs)
+	if !assert.Nil(t, err) {
+		return
+	}
+	positions := workflow.Positions()
+	if !assert.True(t, len(positions) > 2) {
+		return
+	}
+	assert.EqualValues(t, &Position{Type: TypeAction, Name: "updateArch", Line: 7, Column: 3}, positions[0])
+	assert.EqualValues(t, &Position{Type: TypeTask, Name: "init", Line: 17, Column: 3}, positions[1])
+
+	var useCases = []struct {
+		description string
+		line        int
+		expectName  string
+		expectTask  string
+	}{
+		{description: "action key line", line: 7, expectName: "updateArch"},
+		{description: "line within action", line: 11, expectName: "updateArch"},
+		{description: "task line resolves first task action", line: 17, expectName: "cleanup", expectTask: "init"},
+		{description: "nested action line", line: 30, expectName: "siteAggregator", expectTask: "init"},
+	}
+	for _, useCase := range useCases {
+		actual := ActionAt(positions, useCase.line)
+		if !assert.NotNil(t, actual, useCase.description) {
+			continue
+		}
+		assert.Equal(t, useCase.expectName, actual.Name, useCase.description)
+		assert.Equal(t, useCase.expectTask, actual.Task, useCase.description)
+	}
+	assert.Nil(t, ActionAt(positions, 1))
+}
@@ -33,6 +33,10 @@ const (
 	exitKey        = "exit"
 	tagKey         = "tag"
 	defaultPath    = "default"
+	dependsOnKey   = "dependsOn"
+	retryKey       = "retry"
+	timeoutKey     = "timeoutms"
+	concurrencyKey = "maxconcurrency"
 )
 
 var multiActionKeys = []string{"multiaction", "async"}
@@ -44,21 +48,22 @@ type MapEntry struct {
 
 // Inlined represents inline workflow
 type Inlined struct {
-	baseURL    string
-	tagPathURL string
-	name       string
-	Init       interface{}
-	Post       interface{}
-	Logging    *bool
-	Defaults   map[string]interface{}
-	Data       map[string]interface{}
-	Pipeline   []*MapEntry
-	State      data.Map
-	workflow   *Workflow //inline workflow from pipeline
+	baseURL        string
+	tagPathURL     string
+	name           string
+	Init           interface{}
+	Post           interface{}
+	Logging        *bool
+	Defaults       map[string]interface{}
+	Data           map[string]interface{}
+	Pipeline       []*MapEntry
+	State          data.Map
+	MaxConcurrency int       //max number of concurrently running pipeline tasks that declare dependsOn
+	workflow       *Workflow //inline workflow from pipeline
 }
 
 func (p Inlined) updatereservedAttributes(aMap map[string]interface{}) {
-	for _, key := range []string{actionKey, workflowKey, skipKey, whenKey, postKey, initKey, commentsKey, descriptionKey, failKey} {
+	for _, key := range []string{actionKey, workflowKey, skipKey, whenKey, postKey, initKey, commentsKey, descriptionKey, failKey, dependsOnKey} {
 		if val, ok := aMap[key]; ok {
 			if _, has := aMap[ExplicitActionAttributePrefix+key]; has {
 				continue
@@ -67,6 +72,12 @@ func (p Inlined) updatereservedAttributes(aMap map[string]interface{}) {
 			aMap[ExplicitActionAttributePrefix+key] = val
 		}
 	}
+	if val, ok := aMap[retryKey]; ok && toolbox.IsMap(val) { //retry policy, scalar value is left for request
+		if _, has := aMap[ExplicitActionAttributePrefix+retryKey]; !has {
+			delete(aMap, retryKey)
+			aMap[ExplicitActionAttributePrefix+retryKey] = val
+		}
+	}
 	for _, key := range []string{tagKey} {
 		if val, ok := aMap[key]; ok {
 			if _, has := aMap[ExplicitRequestAttributePrefix+key]; has {
@@ -272,6 +283,7 @@ func (p *Inlined) AsWorkflow(name string, baseURL string) (*Workflow, error) {
 	if len(root.Tasks) > 0 {
 		p.normalize(root.TasksNode)
 		workflow.TasksNode = root.TasksNode
+		workflow.TasksNode.MaxConcurrency = p.MaxConcurrency
 	} else {
 		workflow.TasksNode = &TasksNode{
 			Tasks: []*Task{root},
@@ -444,6 +456,9 @@ func (p *Inlined) buildWorkflowNodes(name string, source interface{}, parentTask
 		if reset, ok := actionAttributes[failKey]; ok {
 			task.Fail = toolbox.AsBoolean(reset)
 		}
+		if dependsOn, ok := actionAttributes[strings.ToLower(dependsOnKey)]; ok && !parentTask.multiAction {
+			task.DependsOn = asTaskNames(dependsOn)
+		}
 		return nil
 	}
 
@@ -464,10 +479,18 @@ func (p *Inlined) buildWorkflowNodes(name string, source interface{}, parentTask
 		if isTemplateNode && "template" == textKey {
 			return true
 		}
-		if textKey == loggingKey || textKey == whenKey || textKey == descriptionKey || textKey == failKey { //abstract node attributes
+		if textKey == loggingKey || textKey == whenKey || textKey == descriptionKey || textKey == failKey || textKey == timeoutKey { //abstract node attributes
 			nodeAttributes[textKey] = value
 		}
 		flagAsMultiActionIfMatched(textKey, task, value)
+		switch textKey {
+		case strings.ToLower(dependsOnKey):
+			task.DependsOn = asTaskNames(value)
+			return true
+		case concurrencyKey:
+			task.MaxConcurrency = toolbox.AsInt(value)
+			return true
+		}
 		if value == nil || !toolbox.IsSlice(value) {
 			return true
 		}
@@ -495,6 +518,7 @@ func (p *Inlined) buildWorkflowNodes(name string, source interface{}, parentTask
 						task.When = tempTask.When
 						task.Logging = tempTask.Logging
 						task.Description = tempTask.Description
+						task.TimeoutMs = tempTask.TimeoutMs
 					}
 				}
 			}
@@ -511,3 +535,25 @@ func flagAsMultiActionIfMatched(textKey string, task *Task, value interface{}) {
 		}
 	}
 }
+
+// asTaskNames converts coma separated text or slice into task names
+func asTaskNames(value interface{}) []string {
+	var result = make([]string, 0)
+	if value == nil {
+		return result
+	}
+	var items []interface{}
+	if toolbox.IsSlice(value) {
+		items = toolbox.AsSlice(value)
+	} else {
+		for _, item := range strings.Split(toolbox.AsString(value), ",") {
+			items = append(items, item)
+		}
+	}
+	for _, item := range items {
+		if name := strings.TrimSpace(toolbox.AsString(item)); name != "" {
+			result = append(result, name)
+		}
+	}
+	return result
+}
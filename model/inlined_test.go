@@ -395,6 +395,39 @@ pipeline:
 			"Name": "aero"
 		}
 	]
+}`,
+		},
+		{
+			Description: "task with dependsOn",
+			YAMLData: `pipeline:
+  mysql:
+    action: docker:run
+  aero:
+    action: docker:run
+  app:
+    dependsOn: [mysql, aero]
+    action: exec:run
+`,
+			Expected: `{
+	"tasks": [
+		{
+			"Name": "mysql"
+		},
+		{
+			"Name": "aero"
+		},
+		{
+			"Actions": [
+				{
+					"Action": "run",
+					"Name": "app",
+					"Service": "exec"
+				}
+			],
+			"DependsOn": ["mysql", "aero"],
+			"Name": "app"
+		}
+	]
 }`,
 		},
 	}
@@ -29,6 +29,11 @@ type RunnerOutput interface {
 	IsOutput() bool
 }
 
+// Attachment represent event referencing artifact files (i.e. logs), CLI reports them as test case attachments
+type Attachment interface {
+	Attachments() []string
+}
+
 // Styled represent styled text
 type Styled struct {
 	Text  string
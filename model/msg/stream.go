@@ -0,0 +1,20 @@
+package msg
+
+// StreamEvent represents incremental command output fragment
+type StreamEvent struct {
+	Source string
+	Output string
+	Tail   int `json:",omitempty"`
+}
+
+// Messages returns messages
+func (e *StreamEvent) Messages() []*Message {
+	return []*Message{
+		NewMessage(NewStyled(e.Source, MessageStyleGeneric), NewStyled("stream", MessageStyleGeneric), NewStyled(e.Output, MessageStyleOutput)),
+	}
+}
+
+// NewStreamEvent creates a new stream event, tail is max number of last source output lines a renderer keeps, 0 - no limit
+func NewStreamEvent(source string, output string, tail int) *StreamEvent {
+	return &StreamEvent{Source: source, Output: output, Tail: tail}
+}
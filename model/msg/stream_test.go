@@ -0,0 +1,16 @@
+package msg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewStreamEvent(t *testing.T) {
+	event := NewStreamEvent("localhost", "a\nb\nc\n", 2)
+	assert.Equal(t, "a\nb\nc\n", event.Output)
+	assert.Equal(t, 2, event.Tail)
+	messages := event.Messages()
+	assert.Equal(t, "stream", messages[0].Tag.Text)
+	assert.Equal(t, "a\nb\nc\n", messages[0].Items[0].Text)
+}
@@ -56,6 +56,29 @@ func (p *Process) Push(activity *Activity) {
 	p.Activities.Push(activity)
 }
 
+// Fork returns a process copy with its own task, activity stack and state copy, sharing workflow and tag IDs,
+// it is used to run a task concurrently with other tasks of the same process
+func (p *Process) Fork() *Process {
+	var state data.Map
+	if p.State != nil {
+		state = data.NewMap()
+		state.Apply(p.State)
+	}
+	return &Process{
+		Source:         p.Source,
+		Owner:          p.Owner,
+		TagIDs:         p.TagIDs,
+		HasTagID:       p.HasTagID,
+		Workflow:       p.Workflow,
+		Task:           p.Task,
+		TaskNode:       p.TaskNode,
+		Activities:     p.Activities.Clone(),
+		State:          state,
+		Terminated:     atomic.LoadInt32(&p.Terminated),
+		ExecutionError: &ExecutionError{},
+	}
+}
+
 // Push adds a workflow to the workflow stack.
 func (p *Process) AddTagIDs(tagIDs ...string) {
 	for _, tagID := range tagIDs {
@@ -166,6 +189,15 @@ func (p *Processes) First() *Process {
 	return nil
 }
 
+// Clone returns a new process stack with the same processes, so that nested processes can be pushed independently
+func (p *Processes) Clone() *Processes {
+	p.mux.RLock()
+	defer p.mux.RUnlock()
+	var result = NewProcesses()
+	result.processes = append(result.processes, p.processes...)
+	return result
+}
+
 // NewProcesses creates a new processes
 func NewProcesses() *Processes {
 	return &Processes{
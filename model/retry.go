@@ -0,0 +1,92 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp"
+	"time"
+
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/criteria"
+	"github.com/viant/endly/model/criteria/eval"
+)
+
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryDelayMs     = 500
+	defaultRetryMultiplier  = 2.0
+)
+
+// Retry represents action retry policy, action is retried only on error
+type Retry struct {
+	MaxAttempts int     `description:"max number of attempts including the first one, default 3" yaml:",omitempty"`
+	DelayMs     int     `description:"initial backoff delay, default 500" yaml:",omitempty"`
+	MaxDelayMs  int     `description:"max backoff delay" yaml:",omitempty"`
+	Multiplier  float64 `description:"backoff multiplier, default 2" yaml:",omitempty"`
+	Jitter      float64 `description:"backoff jitter ratio (0-1), i.e 0.2 randomizes delay by +/-20%" yaml:",omitempty"`
+	OnError     string  `description:"regexp matching error message to retry, if empty any error is retried" yaml:",omitempty"`
+	When        string  `description:"retry criteria, evaluated with $error and $attempt" yaml:",omitempty"`
+	onError     *regexp.Regexp
+	whenEval    eval.Compute
+}
+
+// Init initialises retry policy
+func (r *Retry) Init() (err error) {
+	if r.MaxAttempts == 0 {
+		r.MaxAttempts = defaultRetryMaxAttempts
+	}
+	if r.DelayMs == 0 {
+		r.DelayMs = defaultRetryDelayMs
+	}
+	if r.Multiplier == 0 {
+		r.Multiplier = defaultRetryMultiplier
+	}
+	if r.OnError != "" {
+		if r.onError, err = regexp.Compile(r.OnError); err != nil {
+			return fmt.Errorf("invalid retry.onError: %v, %w", r.OnError, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks if retry policy is valid
+func (r *Retry) Validate() error {
+	if r.MaxAttempts < 0 {
+		return fmt.Errorf("invalid retry.maxAttempts: %v", r.MaxAttempts)
+	}
+	if r.Jitter < 0 || r.Jitter > 1 {
+		return fmt.Errorf("invalid retry.jitter: %v, expected value between 0 and 1", r.Jitter)
+	}
+	return nil
+}
+
+// Delay returns backoff delay after supplied attempt (starting from 1)
+func (r *Retry) Delay(attempt int) time.Duration {
+	delay := float64(r.DelayMs) * math.Pow(r.Multiplier, float64(attempt-1))
+	if r.MaxDelayMs > 0 && delay > float64(r.MaxDelayMs) {
+		delay = float64(r.MaxDelayMs)
+	}
+	if r.Jitter > 0 {
+		delay += delay * r.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay) * time.Millisecond
+}
+
+// ShouldRetry returns true if action failed with supplied error at supplied attempt should be retried
+func (r *Retry) ShouldRetry(context *endly.Context, attempt int, err error) (bool, error) {
+	if err == nil || attempt >= r.MaxAttempts {
+		return false, nil
+	}
+	if r.onError != nil && !r.onError.MatchString(err.Error()) {
+		return false, nil
+	}
+	if r.When == "" {
+		return true, nil
+	}
+	var state = context.State()
+	var retryState = state.Clone()
+	retryState.Put("error", err.Error())
+	retryState.Put("attempt", attempt)
+	return criteria.Evaluate(context, retryState, r.When, &r.whenEval, "Retry.When", true)
+}
@@ -0,0 +1,109 @@
+package model
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+)
+
+func TestRetry_Delay(t *testing.T) {
+	var useCases = []struct {
+		description string
+		retry       *Retry
+		attempt     int
+		expect      time.Duration
+	}{
+		{
+			description: "default initial delay",
+			retry:       &Retry{},
+			attempt:     1,
+			expect:      500 * time.Millisecond,
+		},
+		{
+			description: "exponential backoff",
+			retry:       &Retry{DelayMs: 100},
+			attempt:     4,
+			expect:      800 * time.Millisecond,
+		},
+		{
+			description: "max delay cap",
+			retry:       &Retry{DelayMs: 100, MaxDelayMs: 300},
+			attempt:     4,
+			expect:      300 * time.Millisecond,
+		},
+	}
+	for _, useCase := range useCases {
+		assert.Nil(t, useCase.retry.Init(), useCase.description)
+		assert.Equal(t, useCase.expect, useCase.retry.Delay(useCase.attempt), useCase.description)
+	}
+}
+
+func TestRetry_ShouldRetry(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(nil)
+	var useCases = []struct {
+		description string
+		retry       *Retry
+		attempt     int
+		err         error
+		expect      bool
+	}{
+		{
+			description: "any error",
+			retry:       &Retry{},
+			attempt:     1,
+			err:         errors.New("connection refused"),
+			expect:      true,
+		},
+		{
+			description: "max attempts reached",
+			retry:       &Retry{MaxAttempts: 2},
+			attempt:     2,
+			err:         errors.New("connection refused"),
+		},
+		{
+			description: "error matched",
+			retry:       &Retry{OnError: "refused|timeout"},
+			attempt:     1,
+			err:         errors.New("dial tcp: i/o timeout"),
+			expect:      true,
+		},
+		{
+			description: "error not matched",
+			retry:       &Retry{OnError: "refused|timeout"},
+			attempt:     1,
+			err:         errors.New("404 not found"),
+		},
+		{
+			description: "no error",
+			retry:       &Retry{},
+			attempt:     1,
+		},
+	}
+	for _, useCase := range useCases {
+		assert.Nil(t, useCase.retry.Init(), useCase.description)
+		actual, err := useCase.retry.ShouldRetry(context, useCase.attempt, useCase.err)
+		assert.Nil(t, err, useCase.description)
+		assert.Equal(t, useCase.expect, actual, useCase.description)
+	}
+}
+
+func TestAction_CloneRetry(t *testing.T) {
+	action := &Action{
+		AbstractNode:   &AbstractNode{},
+		ServiceRequest: &ServiceRequest{},
+		MetaTag:        &MetaTag{},
+		Repeater:       &Repeater{},
+		Retry:          &Retry{MaxAttempts: 3},
+	}
+	cloned := action.Clone()
+	if !assert.NotNil(t, cloned.Retry) {
+		return
+	}
+	cloned.Retry.MaxAttempts = 5
+	assert.Equal(t, 3, action.Retry.MaxAttempts)
+	assert.Nil(t, (&Action{AbstractNode: &AbstractNode{}, ServiceRequest: &ServiceRequest{}, MetaTag: &MetaTag{}, Repeater: &Repeater{}}).Clone().Retry)
+}
@@ -8,6 +8,7 @@ type Task struct {
 	*TasksNode    ` yaml:",inline"`
 	Fail          bool      ` yaml:",omitempty"` //controls if return fail status workflow on catch task
 	Template      *Template ` yaml:",omitempty"`
+	DependsOn     []string  `description:"tasks that have to complete before this task runs, tasks without dependency run concurrently" yaml:"dependsOn,omitempty"`
 	//internal only for inline workflow meta data
 
 	multiAction bool //flag directing grouping actions (otherwise each action has its own task)
@@ -34,6 +35,9 @@ func (t *Task) Clone() *Task {
 		result.Actions[i] = item.Clone()
 	}
 	result.TasksNode = t.TasksNode.Clone()
+	if len(t.DependsOn) > 0 {
+		result.DependsOn = append([]string{}, t.DependsOn...)
+	}
 	result.AbstractNode = t.AbstractNode.Clone()
 	if t.MetaTag != nil {
 		tag := *t.MetaTag
@@ -7,9 +7,10 @@ import (
 
 // TasksNode represents a task node
 type TasksNode struct {
-	Tasks        Tasks  ` yaml:",omitempty"` //sub tasks
-	OnErrorTask  string ` yaml:",omitempty"` //task that will run if error occur, the final workflow will return this task response
-	DeferredTask string ` yaml:",omitempty"` //task that will always run if there has been previous  error or not
+	Tasks          Tasks  ` yaml:",omitempty"` //sub tasks
+	OnErrorTask    string ` yaml:",omitempty"` //task that will run if error occur, the final workflow will return this task response
+	DeferredTask   string ` yaml:",omitempty"` //task that will always run if there has been previous  error or not
+	MaxConcurrency int    ` yaml:",omitempty"` //max number of tasks running concurrently when tasks declare dependsOn
 }
 
 type Tasks []*Task
@@ -24,9 +25,10 @@ func (t *TasksNode) Select(selector TasksSelector) *TasksNode {
 		allowed[task] = true
 	}
 	var result = &TasksNode{
-		OnErrorTask:  t.OnErrorTask,
-		DeferredTask: t.DeferredTask,
-		Tasks:        []*Task{},
+		OnErrorTask:    t.OnErrorTask,
+		DeferredTask:   t.DeferredTask,
+		MaxConcurrency: t.MaxConcurrency,
+		Tasks:          []*Task{},
 	}
 
 	if result.DeferredTask != "" {
@@ -82,6 +84,36 @@ func (t *TasksNode) Has(name string) bool {
 	return err == nil
 }
 
+// HasDependencies returns true if any of the tasks declares dependsOn
+func (t *TasksNode) HasDependencies() bool {
+	for _, task := range t.Tasks {
+		if len(task.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *TasksNode) validateDependencies() error {
+	var names = make(map[string]bool)
+	for _, task := range t.Tasks {
+		names[task.Name] = true
+	}
+	for _, task := range t.Tasks {
+		for _, dependency := range task.DependsOn {
+			if !names[dependency] {
+				return fmt.Errorf("invalid task %v dependsOn: %v, only sibling tasks are supported", task.Name, dependency)
+			}
+		}
+		if task.TasksNode != nil {
+			if err := task.TasksNode.validateDependencies(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 func (t *TasksNode) Clone() *TasksNode {
 	ret := *t
 	return &ret
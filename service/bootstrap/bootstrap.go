@@ -6,6 +6,7 @@ import (
 	"flag"
 	"fmt"
 	"github.com/viant/afs"
+	"github.com/viant/endly/internal/lint"
 	"github.com/viant/endly/internal/webplanner"
 	"github.com/viant/endly/model/location"
 	loader "github.com/viant/endly/model/project/loader"
@@ -61,9 +62,11 @@ import (
 	_ "github.com/viant/endly/service/testing/log"
 	_ "github.com/viant/endly/service/testing/validator"
 
+	_ "github.com/viant/endly/service/testing/endpoint/grpc"
 	_ "github.com/viant/endly/service/testing/endpoint/http"
 	_ "github.com/viant/endly/service/testing/endpoint/smtp"
 	_ "github.com/viant/endly/service/testing/msg"
+	_ "github.com/viant/endly/service/testing/runner/grpc"
 	_ "github.com/viant/endly/service/testing/runner/http"
 	_ "github.com/viant/endly/service/testing/runner/rest"
 	_ "github.com/viant/endly/service/testing/runner/webdriver"
@@ -167,6 +170,12 @@ func init() {
 	flag.String("run", "", "run specified service action it expect valid service:action to run")
 	flag.String("req", "", "optional request URL when run option is specified")
 	flag.String("w", "", "start HTTP webdriver test planner")
+	flag.Bool("checkpoint", false, "persist workflow checkpoint after each completed task in log directory")
+	flag.String("resume", "", "<sessionID> resume interrupted workflow run from the first unfinished task")
+	flag.Bool("dryrun", false, "render and validate fully resolved service requests without executing them")
+	flag.Bool("lint", false, "validate workflow without running it: service actions, request attributes, goto targets and variables")
+	flag.String("dap", "", "<address> start Debug Adapter Protocol server for IDE workflow debugging, i.e -dap=:4711")
+	flag.String("debug", "", "run workflow in interactive debugger; optional coma separated breakpoints: workflow/task/action or #tagID, i.e -debug=build/*,#test1")
 
 	_ = mysql.SetLogger(&emptyLogger{})
 
@@ -217,6 +226,15 @@ func normalizeDFlag() {
 	os.Args = out
 }
 
+func normalizeDebugFlag() {
+	// allow bare `-debug` by converting it to `-debug=true`, breakpoints have to be supplied as -debug=<selectors>
+	for i, arg := range os.Args {
+		if arg == "-debug" || arg == "--debug" {
+			os.Args[i] = "-debug=true"
+		}
+	}
+}
+
 func Bootstrap() {
 
 	flagset := make(map[string]string)
@@ -224,6 +242,7 @@ func Bootstrap() {
 
 	// Normalize flags that can be used without explicit values
 	normalizeDFlag()
+	normalizeDebugFlag()
 	detectFirstArguments(flagset)
 	flag.Parse()
 
@@ -316,6 +335,10 @@ func Bootstrap() {
 		printWorkflowTasks(request)
 		return
 	}
+	if value, ok := flagset["lint"]; ok && toolbox.AsBoolean(value) {
+		lintWorkflow(request)
+		return
+	}
 	interactive, ok := flagset["m"]
 	runWorkflow(request, ok && toolbox.AsBoolean(interactive))
 }
@@ -658,6 +681,30 @@ func getWorkflow(request *workflow.RunRequest) (*model.Workflow, error) {
 	return nil, fmt.Errorf("only yaml workflow are supported")
 }
 
+func lintWorkflow(request *workflow.RunRequest) {
+	aWorkflow, err := getWorkflow(request)
+	if err != nil && request.URL != "" { //regular run request referencing workflow
+		var workflowRequest *workflow.RunRequest
+		if workflowRequest, err = loadInlineWorkflow(context.Background(), request.URL); err == nil {
+			aWorkflow, err = getWorkflow(workflowRequest)
+		}
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	issues, err := lint.New(endly.New()).Lint(context.Background(), aWorkflow, request.Params)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, issue := range issues {
+		_, _ = fmt.Fprintln(os.Stderr, issue.String())
+	}
+	_, _ = fmt.Fprintf(os.Stderr, "workflow '%v': %v issue(s)\n", aWorkflow.Name, len(issues))
+	if issues.HasErrors() {
+		os.Exit(1)
+	}
+}
+
 func printWorkflow(request *workflow.RunRequest, format string) {
 
 	workflowLoader := loader.New()
@@ -827,6 +874,28 @@ func updateBaseRunWithOptions(request *workflow.RunRequest, flagset map[string]s
 	if value, ok := flagset["e"]; ok {
 		request.FailureCount = toolbox.AsInt(value)
 	}
+	if value, ok := flagset["checkpoint"]; ok {
+		request.Checkpoint = toolbox.AsBoolean(value)
+	}
+	if value, ok := flagset["resume"]; ok {
+		request.Resume = value
+		request.Checkpoint = true
+	}
+	if value, ok := flagset["debug"]; ok && !strings.EqualFold(value, "false") {
+		request.Debug = true
+		if !strings.EqualFold(value, "true") {
+			request.Breakpoints = strings.Split(value, ",")
+		}
+	}
+	if value, ok := flagset["dryrun"]; ok {
+		request.DryRun = toolbox.AsBoolean(value)
+	}
+	if value, ok := flagset["dap"]; ok {
+		request.DAPAddress = value
+	}
+	if request.Checkpoint && request.LogDirectory == "" {
+		request.LogDirectory = flag.Lookup("l").Value.String()
+	}
 	return nil
 }
 
@@ -0,0 +1,274 @@
+package docker
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/viant/toolbox"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	//StackLabel represents container, network and volume label with stack project name
+	StackLabel = "endly.stack"
+	//StackServiceLabel represents container label with stack service name
+	StackServiceLabel = "endly.stack.service"
+	defaultProject    = "endly"
+)
+
+var invalidProjectChars = regexp.MustCompile("[^a-z0-9_-]+")
+
+type composeFile struct {
+	Name     string                     `yaml:"name"`
+	Services map[string]*composeService `yaml:"services"`
+}
+
+type composeService struct {
+	Image         string              `yaml:"image"`
+	ContainerName string              `yaml:"container_name"`
+	Command       interface{}         `yaml:"command"`
+	Entrypoint    interface{}         `yaml:"entrypoint"`
+	Environment   interface{}         `yaml:"environment"`
+	Ports         []interface{}       `yaml:"ports"`
+	Volumes       []string            `yaml:"volumes"`
+	WorkingDir    string              `yaml:"working_dir"`
+	DependsOn     interface{}         `yaml:"depends_on"`
+	Healthcheck   *composeHealthcheck `yaml:"healthcheck"`
+	Platform      string              `yaml:"platform"`
+}
+
+type composeHealthcheck struct {
+	Test        interface{} `yaml:"test"`
+	Interval    string      `yaml:"interval"`
+	Timeout     string      `yaml:"timeout"`
+	Retries     int         `yaml:"retries"`
+	StartPeriod string      `yaml:"start_period"`
+	Disable     bool        `yaml:"disable"`
+}
+
+// decodeComposeFile decodes compose file content into project name and stack services, relative bind mounts are resolved with baseDirectory
+func decodeComposeFile(content []byte, baseDirectory string) (string, map[string]*StackService, error) {
+	compose := &composeFile{}
+	if err := yaml.Unmarshal(content, compose); err != nil {
+		return "", nil, fmt.Errorf("failed to decode compose file, %w", err)
+	}
+	var result = make(map[string]*StackService)
+	for name, service := range compose.Services {
+		stackService, err := service.stackService(baseDirectory)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid service %v, %w", name, err)
+		}
+		result[name] = stackService
+	}
+	return compose.Name, result, nil
+}
+
+func (s *composeService) stackService(baseDirectory string) (*StackService, error) {
+	result := &StackService{
+		RunRequest: &RunRequest{
+			Image:      s.Image,
+			Name:       s.ContainerName,
+			Workdir:    s.WorkingDir,
+			Platform:   s.Platform,
+			Cmd:        asCommand(s.Command),
+			Entrypoint: asCommand(s.Entrypoint),
+			Env:        asEnv(s.Environment),
+		},
+		DependsOn: asDependsOn(s.DependsOn),
+	}
+	for _, item := range s.Ports {
+		parts := strings.Split(toolbox.AsString(item), ":")
+		if len(parts) < 2 { //container port only is not published
+			continue
+		}
+		if result.Ports == nil {
+			result.Ports = make(map[string]string)
+		}
+		result.Ports[strings.Join(parts[:len(parts)-1], ":")] = parts[len(parts)-1]
+	}
+	for _, volume := range s.Volumes {
+		parts := strings.Split(volume, ":")
+		if len(parts) < 2 {
+			continue
+		}
+		source, target := parts[0], strings.Join(parts[1:], ":") //target keeps mode, i.e. /data:ro
+		if isBindSource(source) {
+			if !strings.HasPrefix(source, "/") && !strings.HasPrefix(source, "~") {
+				source = path.Join(baseDirectory, source)
+			}
+			if result.Mount == nil {
+				result.Mount = make(map[string]string)
+			}
+			result.Mount[source] = target
+			continue
+		}
+		if result.Volumes == nil {
+			result.Volumes = make(map[string]string)
+		}
+		result.Volumes[source] = target
+	}
+	if s.Healthcheck != nil && !s.Healthcheck.Disable {
+		healthCheck, err := s.Healthcheck.healthCheck()
+		if err != nil {
+			return nil, err
+		}
+		result.HealthCheck = healthCheck
+	}
+	return result, nil
+}
+
+func (h *composeHealthcheck) healthCheck() (*HealthCheck, error) {
+	result := &HealthCheck{Retries: h.Retries, Test: asCommand(h.Test)}
+	if text, ok := h.Test.(string); ok {
+		result.Test = []string{text}
+	}
+	var err error
+	if result.IntervalMs, err = asMs(h.Interval); err != nil {
+		return nil, err
+	}
+	if result.TimeoutMs, err = asMs(h.Timeout); err != nil {
+		return nil, err
+	}
+	if result.StartPeriodMs, err = asMs(h.StartPeriod); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Config returns container health config
+func (h *HealthCheck) Config() *container.HealthConfig {
+	test := h.Test
+	if len(test) == 1 && test[0] != "NONE" {
+		test = []string{"CMD-SHELL", test[0]}
+	}
+	return &container.HealthConfig{
+		Test:        test,
+		Interval:    time.Duration(h.IntervalMs) * time.Millisecond,
+		Timeout:     time.Duration(h.TimeoutMs) * time.Millisecond,
+		StartPeriod: time.Duration(h.StartPeriodMs) * time.Millisecond,
+		Retries:     h.Retries,
+	}
+}
+
+// stackOrder returns service names in dependency order
+func stackOrder(services map[string]*StackService) ([]string, error) {
+	var names = make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var result = make([]string, 0, len(services))
+	var state = make(map[string]int) //1: visiting, 2: visited
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case 1:
+			return fmt.Errorf("circular services dependency: %v", strings.Join(append(path, name), " -> "))
+		case 2:
+			return nil
+		}
+		service, ok := services[name]
+		if !ok {
+			return fmt.Errorf("unknown service %v, required by %v", name, path[len(path)-1])
+		}
+		state[name] = 1
+		for _, dependency := range service.DependsOn {
+			if err := visit(dependency, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = 2
+		result = append(result, name)
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name, []string{}); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// projectName returns normalized project name
+func projectName(candidates ...string) string {
+	for _, candidate := range candidates {
+		candidate = invalidProjectChars.ReplaceAllString(strings.ToLower(candidate), "")
+		if candidate != "" && candidate != "." {
+			return candidate
+		}
+	}
+	return defaultProject
+}
+
+func isBindSource(source string) bool {
+	return strings.HasPrefix(source, ".") || strings.HasPrefix(source, "/") || strings.HasPrefix(source, "~")
+}
+
+func asMs(duration string) (int, error) {
+	if duration == "" {
+		return 0, nil
+	}
+	result, err := time.ParseDuration(duration)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration: %v, %w", duration, err)
+	}
+	return int(result / time.Millisecond), nil
+}
+
+func asCommand(value interface{}) []string {
+	switch actual := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return strings.Fields(actual)
+	default:
+		var result = make([]string, 0)
+		for _, item := range toolbox.AsSlice(actual) {
+			result = append(result, toolbox.AsString(item))
+		}
+		return result
+	}
+}
+
+func asEnv(value interface{}) map[string]string {
+	if value == nil {
+		return nil
+	}
+	var result = make(map[string]string)
+	if toolbox.IsMap(value) {
+		for key, item := range toolbox.AsMap(value) {
+			result[key] = toolbox.AsString(item)
+		}
+		return result
+	}
+	for _, item := range toolbox.AsSlice(value) {
+		pair := strings.SplitN(toolbox.AsString(item), "=", 2)
+		if len(pair) == 2 {
+			result[pair[0]] = pair[1]
+		}
+	}
+	return result
+}
+
+func asDependsOn(value interface{}) []string {
+	if value == nil {
+		return nil
+	}
+	var result = make([]string, 0)
+	if toolbox.IsMap(value) {
+		for key := range toolbox.AsMap(value) {
+			result = append(result, key)
+		}
+		sort.Strings(result)
+		return result
+	}
+	for _, item := range toolbox.AsSlice(value) {
+		result = append(result, toolbox.AsString(item))
+	}
+	return result
+}
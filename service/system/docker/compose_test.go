@@ -0,0 +1,166 @@
+package docker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/toolbox"
+)
+
+func TestDecodeComposeFile(t *testing.T) {
+	content := `
+name: e2e
+services:
+  db:
+    image: postgres:15
+    environment:
+      POSTGRES_PASSWORD: secret
+    ports:
+      - "5432:5432"
+    volumes:
+      - dbdata:/var/lib/postgresql/data
+      - ./init:/docker-entrypoint-initdb.d:ro
+    healthcheck:
+      test: ["CMD-SHELL", "pg_isready -U postgres"]
+      interval: 2s
+      timeout: 1s
+      retries: 10
+  app:
+    image: app:latest
+    command: ./app -port 8080
+    environment:
+      - DB_HOST=db
+    ports:
+      - 127.0.0.1:8080:8080
+    depends_on:
+      db:
+        condition: service_healthy
+volumes:
+  dbdata: {}
+`
+	project, services, err := decodeComposeFile([]byte(content), "/tmp/stack")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, "e2e", project)
+	assert.Equal(t, 2, len(services))
+
+	db := services["db"]
+	assert.Equal(t, "postgres:15", db.Image)
+	assert.Equal(t, map[string]string{"POSTGRES_PASSWORD": "secret"}, db.Env)
+	assert.Equal(t, map[string]string{"5432": "5432"}, db.Ports)
+	assert.Equal(t, map[string]string{"dbdata": "/var/lib/postgresql/data"}, db.Volumes)
+	assert.Equal(t, map[string]string{"/tmp/stack/init": "/docker-entrypoint-initdb.d:ro"}, db.Mount)
+	assert.Equal(t, &HealthCheck{Test: []string{"CMD-SHELL", "pg_isready -U postgres"}, IntervalMs: 2000, TimeoutMs: 1000, Retries: 10}, db.HealthCheck)
+
+	app := services["app"]
+	assert.Equal(t, []string{"./app", "-port", "8080"}, app.Cmd)
+	assert.Equal(t, map[string]string{"DB_HOST": "db"}, app.Env)
+	assert.Equal(t, map[string]string{"127.0.0.1:8080": "8080"}, app.Ports)
+	assert.Equal(t, []string{"db"}, app.DependsOn)
+
+	order, err := stackOrder(services)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"db", "app"}, order)
+}
+
+func TestService_StackRunRequest(t *testing.T) {
+	stackService := &StackService{RunRequest: &RunRequest{
+		Image: "app:latest",
+		Ports: map[string]string{"127.0.0.1:8080": "80"},
+		Mount: map[string]string{"/tmp/stack/config": "/config:ro"},
+	}}
+	srv := &service{}
+	for i := 0; i < 2; i++ {
+		request, err := srv.stackRunRequest(nil, "e2e", "e2e_default", "app", stackService)
+		if !assert.Nil(t, err) {
+			return
+		}
+		assert.Equal(t, "e2e_app", request.Name)
+		assert.Equal(t, "app", request.Config.Labels[StackServiceLabel])
+		assert.Equal(t, []mount.Mount{{Type: mount.TypeBind, Source: "/tmp/stack/config", Target: "/config", ReadOnly: true}}, request.HostConfig.Mounts)
+		assert.Equal(t, []nat.PortBinding{{HostIP: "127.0.0.1", HostPort: "8080"}}, request.HostConfig.PortBindings["80/tcp"])
+	}
+	assert.Equal(t, "", stackService.Name)
+	assert.Nil(t, stackService.Config)
+	assert.Nil(t, stackService.HostConfig)
+}
+
+func TestStackOrder(t *testing.T) {
+	var useCases = []struct {
+		description string
+		services    map[string]*StackService
+		expect      []string
+		hasError    bool
+	}{
+		{
+			description: "dependency chain",
+			services: map[string]*StackService{
+				"app":   {DependsOn: []string{"cache", "db"}},
+				"cache": {},
+				"db":    {DependsOn: []string{"cache"}},
+			},
+			expect: []string{"cache", "db", "app"},
+		},
+		{
+			description: "circular dependency",
+			services: map[string]*StackService{
+				"a": {DependsOn: []string{"b"}},
+				"b": {DependsOn: []string{"a"}},
+			},
+			hasError: true,
+		},
+		{
+			description: "unknown dependency",
+			services: map[string]*StackService{
+				"a": {DependsOn: []string{"x"}},
+			},
+			hasError: true,
+		},
+	}
+	for _, useCase := range useCases {
+		actual, err := stackOrder(useCase.services)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		assert.Nil(t, err, useCase.description)
+		assert.Equal(t, useCase.expect, actual, useCase.description)
+	}
+}
+
+func TestUpRequest_Services(t *testing.T) {
+	request := &UpRequest{}
+	err := toolbox.DefaultConverter.AssignConverted(request, map[string]interface{}{
+		"Project": "My Stack!",
+		"Services": map[string]interface{}{
+			"db": map[string]interface{}{
+				"Image": "mysql:8",
+				"Env":   map[string]interface{}{"MYSQL_ROOT_PASSWORD": "dev"},
+				"HealthCheck": map[string]interface{}{
+					"Test":       []interface{}{"mysqladmin ping -h 127.0.0.1"},
+					"IntervalMs": 1000,
+				},
+			},
+			"app": map[string]interface{}{
+				"Image":     "app:latest",
+				"DependsOn": []interface{}{"db"},
+			},
+		},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Nil(t, request.Init())
+	assert.Nil(t, request.Validate())
+	assert.Equal(t, "mystack", projectName(request.Project))
+	assert.Equal(t, "endly", projectName("", "."))
+	assert.Equal(t, "mysql:8", request.Services["db"].Image)
+	assert.Equal(t, []string{"db"}, request.Services["app"].DependsOn)
+	config := request.Services["db"].HealthCheck.Config()
+	assert.Equal(t, []string{"CMD-SHELL", "mysqladmin ping -h 127.0.0.1"}, config.Test)
+	assert.Equal(t, time.Second, config.Interval)
+}
@@ -4,15 +4,19 @@ import (
 	"fmt"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/mount"
 	network "github.com/docker/docker/api/types/network"
 	"github.com/docker/go-connections/nat"
 	"github.com/go-errors/errors"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/viant/endly/model"
 	"github.com/viant/endly/model/location"
 	"github.com/viant/scy/cred/secret"
 	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+	"regexp"
 	"strings"
 )
 
@@ -23,8 +27,8 @@ type RunRequest struct {
 	Image       string            `required:"true" description:"container image to runAdapter" example:"mysql:5.6"`
 	Port        string            `description:"publish a container’s port(s) to the host, docker -p option"`
 	Env         map[string]string `description:"set docker container an environment variable, docker -e KEY=VAL  option"`
-	Mount       map[string]string `description:"bind mount a volume, docker -v option"`
-	Ports       map[string]string `description:"publish a container’s port(s) to the host, docker -p option"`
+	Mount       map[string]string `description:"bind mount a volume, docker -v option, target with :ro suffix is mounted read only"`
+	Ports       map[string]string `description:"publish a container’s port(s) to the host, docker -p option, host port can be prefixed with host IP, i.e. 127.0.0.1:8080"`
 	Workdir     string            `description:"working directory inside the container, docker -w option"`
 	Reuse       bool              `description:"reuse existing container if exists, otherwise always removes"`
 	Block       bool              `description:"block until the container exits (wait for not-running)"`
@@ -38,10 +42,23 @@ type RunRequest struct {
 	PlatformSpec     *ocispec.Platform
 	ContainerName    string
 
-	Secrets map[secret.Key]secret.Resource `description:"map of secrets used within env"`
+	Secrets   map[secret.Key]secret.Resource `description:"map of secrets used within env"`
+	Readiness *Readiness                     `description:"readiness checks, run waits until all specified checks pass"`
 	image.PullOptions
 }
 
+// Readiness represents container readiness checks
+type Readiness struct {
+	Health     bool   `description:"wait for docker HEALTHCHECK healthy status"`
+	TCP        string `description:"address that has to accept connections, i.e. 127.0.0.1:3306 or 3306"`
+	HTTP       string `description:"URL that has to return 2xx status code"`
+	Log        string `description:"regular expression that container logs have to match"`
+	Completed  bool   `description:"flag to treat container that exited with 0 code as ready, i.e. one-off init container"`
+	TimeoutMs  int    `description:"max time to wait, default 60000"`
+	IntervalMs int    `description:"checks interval, default 500"`
+	TailLines  int    `description:"number of container last log lines reported when container is not ready, default 20"`
+}
+
 type RunResponse struct {
 	ContainerID string
 	Status      string
@@ -223,10 +240,12 @@ func (r *RunRequest) Init() error {
 			}
 			source = expandHomeDirectory(source)
 			source = location.NewResource(source).Path()
+			target, readOnly := mountTarget(dest)
 			r.HostConfig.Mounts = append(r.HostConfig.Mounts, mount.Mount{
-				Type:   mount.TypeBind,
-				Source: source,
-				Target: dest,
+				Type:     mount.TypeBind,
+				Source:   source,
+				Target:   target,
+				ReadOnly: readOnly,
 			})
 		}
 	}
@@ -242,7 +261,11 @@ func (r *RunRequest) Init() error {
 			if !strings.Contains(dest, "/") {
 				dest += "/tcp"
 			}
-			portsBindings[nat.Port(dest)] = []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: string(source)}}
+			hostIP := "0.0.0.0"
+			if index := strings.LastIndex(source, ":"); index != -1 { //i.e. 127.0.0.1:8080
+				hostIP, source = source[:index], source[index+1:]
+			}
+			portsBindings[nat.Port(dest)] = []nat.PortBinding{{HostIP: hostIP, HostPort: source}}
 		}
 		if err := toolbox.DefaultConverter.AssignConverted(&r.HostConfig.PortBindings, portsBindings); err != nil {
 			return err
@@ -267,13 +290,85 @@ func (r *RunRequest) Init() error {
 	if r.Name != "" {
 		r.ContainerName = r.Name
 	}
+	if r.Readiness != nil {
+		return r.Readiness.Init()
+	}
 	return nil
 }
 
+// clone returns run request copy with its own container config, host config and labels
+func (r *RunRequest) clone() *RunRequest {
+	result := *r
+	if r.Config != nil {
+		config := *r.Config
+		if r.Config.Labels != nil {
+			config.Labels = make(map[string]string, len(r.Config.Labels))
+			for k, v := range r.Config.Labels {
+				config.Labels[k] = v
+			}
+		}
+		result.Config = &config
+	}
+	if r.HostConfig != nil {
+		hostConfig := *r.HostConfig
+		hostConfig.Mounts = append([]mount.Mount{}, r.HostConfig.Mounts...)
+		result.HostConfig = &hostConfig
+	}
+	if r.Readiness != nil {
+		readiness := *r.Readiness
+		result.Readiness = &readiness
+	}
+	return &result
+}
+
+// mountTarget returns container path and read only flag for target with optional mode, i.e. /data:ro
+func mountTarget(target string) (string, bool) {
+	if index := strings.LastIndex(target, ":"); index != -1 {
+		switch target[index+1:] {
+		case "ro":
+			return target[:index], true
+		case "rw":
+			return target[:index], false
+		}
+	}
+	return target, false
+}
+
 func (r *RunRequest) Validate() error {
 	if r.Config.Image == "" {
 		return errors.New("image was empty")
 	}
+	if r.Readiness != nil {
+		return r.Readiness.Validate()
+	}
+	return nil
+}
+
+func (r *Readiness) Init() error {
+	if r.TimeoutMs == 0 {
+		r.TimeoutMs = 60000
+	}
+	if r.IntervalMs == 0 {
+		r.IntervalMs = 500
+	}
+	if r.TailLines == 0 {
+		r.TailLines = 20
+	}
+	if r.TCP != "" && !strings.Contains(r.TCP, ":") {
+		r.TCP = "127.0.0.1:" + r.TCP
+	}
+	return nil
+}
+
+func (r *Readiness) Validate() error {
+	if !r.Health && !r.Completed && r.TCP == "" && r.HTTP == "" && r.Log == "" {
+		return errors.New("readiness checks were empty")
+	}
+	if r.Log != "" {
+		if _, err := regexp.Compile(r.Log); err != nil {
+			return fmt.Errorf("invalid readiness log expression: %v, %v", r.Log, err)
+		}
+	}
 	return nil
 }
 
@@ -347,3 +442,301 @@ func (r *LogsRequest) Init() error {
 	}
 	return nil
 }
+
+// UpRequest represents docker compose style stack up request
+type UpRequest struct {
+	Project     string                   `description:"stack name used for network, volume and default container names, default: compose name, compose file directory or endly"`
+	ComposeFile string                   `description:"docker compose file location"`
+	Services    map[string]*StackService `description:"stack services, merged with compose file services"`
+	WaitTimeMs  int                      `description:"max time to wait for a service to become healthy, default 60000"`
+}
+
+// StackService represents stack service built on run request
+type StackService struct {
+	*RunRequest `json:",inline" yaml:",inline"`
+	DependsOn   []string          `description:"services that have to be healthy before this service is started"`
+	Volumes     map[string]string `description:"stack named volume to container path mapping, path with :ro suffix is mounted read only"`
+	HealthCheck *HealthCheck      `description:"container health check, service is healthy when the check passes or when running if no check is defined"`
+}
+
+// HealthCheck represents container health check
+type HealthCheck struct {
+	Test          []string `description:"health check command, i.e. [CMD-SHELL, pg_isready -U postgres], a single command is run with CMD-SHELL"`
+	IntervalMs    int
+	TimeoutMs     int
+	StartPeriodMs int
+	Retries       int
+}
+
+// UpResponse represents stack up response
+type UpResponse struct {
+	Project  string
+	Network  string
+	Services []*StackServiceInfo
+}
+
+// StackServiceInfo represents started stack service
+type StackServiceInfo struct {
+	Name          string
+	ContainerName string
+	ContainerID   string
+	Status        string
+	Health        string `json:",omitempty"`
+}
+
+// DownRequest represents docker compose style stack down request
+type DownRequest struct {
+	Project     string `description:"stack name, default: compose name, compose file directory or endly"`
+	ComposeFile string `description:"docker compose file location used to resolve default project name"`
+	Volumes     bool   `description:"flag to remove stack named volumes"`
+}
+
+// DownResponse represents stack down response
+type DownResponse struct {
+	Project    string
+	Containers []string
+	Network    string   `json:",omitempty"`
+	Volumes    []string `json:",omitempty"`
+}
+
+func (r *UpRequest) Init() error {
+	if r.WaitTimeMs == 0 {
+		r.WaitTimeMs = 60000
+	}
+	return nil
+}
+
+func (r *UpRequest) Validate() error {
+	if r.ComposeFile == "" && len(r.Services) == 0 {
+		return errors.New("composeFile and services were empty")
+	}
+	return nil
+}
+
+// ExecRequest represents docker exec request, runs a command inside running container
+type ExecRequest struct {
+	Name       string            `required:"true" description:"container name or ID"`
+	Command    string            `description:"shell command, executed with /bin/sh -c"`
+	Cmd        []string          `description:"command with arguments, used when command is empty"`
+	Env        map[string]string `description:"command environment variables"`
+	Workdir    string            `description:"command working directory"`
+	User       string            `description:"user that runs the command, i.e. root or 1000:1000"`
+	Tty        bool              `description:"allocate pseudo TTY, stdout and stderr are merged into stdout"`
+	Stdin      string            `description:"content passed to the command standard input"`
+	TimeoutMs  int               `description:"max command execution time, 0 - no timeout"`
+	CheckError bool              `description:"fail when command exit code is not zero"`
+	Extract    model.Extracts    `description:"stdout data extraction instruction"`
+	Errors     []string          `description:"fragments that will terminate execution with error if matched with standard output"`
+	Success    []string          `description:"if specified absence of all of the these fragment will terminate execution with error"`
+}
+
+// ExecResponse represents docker exec response
+type ExecResponse struct {
+	ContainerID string
+	ExitCode    int
+	Stdout      string
+	Stderr      string
+	Data        data.Map `json:",omitempty"`
+}
+
+func (r *ExecRequest) Validate() error {
+	if r.Name == "" {
+		return errors.New("name was empty")
+	}
+	if r.Command == "" && len(r.Cmd) == 0 {
+		return errors.New("command was empty")
+	}
+	return nil
+}
+
+// Options returns exec options
+func (r *ExecRequest) Options() container.ExecOptions {
+	result := container.ExecOptions{
+		User:         r.User,
+		Tty:          r.Tty,
+		AttachStdin:  r.Stdin != "",
+		AttachStdout: true,
+		AttachStderr: true,
+		WorkingDir:   r.Workdir,
+		Cmd:          r.Cmd,
+	}
+	if r.Command != "" {
+		result.Cmd = []string{"/bin/sh", "-c", r.Command}
+	}
+	for k, v := range r.Env {
+		result.Env = append(result.Env, fmt.Sprintf("%v=%v", k, v))
+	}
+	return result
+}
+
+// CreateNetworkRequest represents network create request, existing network with the same name is reused
+type CreateNetworkRequest struct {
+	Name       string            `required:"true" description:"network name"`
+	Driver     string            `description:"network driver, default bridge"`
+	Internal   bool              `description:"restrict external access to the network"`
+	Attachable bool              `description:"enable manual container attachment"`
+	Subnet     string            `description:"subnet in CIDR format, i.e. 172.28.0.0/16"`
+	Gateway    string            `description:"subnet gateway, i.e. 172.28.0.1"`
+	Labels     map[string]string `description:"network labels, i.e. to prune networks created by a test session"`
+}
+
+// CreateNetworkResponse represents network create response
+type CreateNetworkResponse struct {
+	NetworkID string
+	Name      string
+	Reused    bool
+}
+
+// RemoveNetworkRequest represents network remove request, missing networks are ignored
+type RemoveNetworkRequest struct {
+	Name  string
+	Names []string
+	Force bool `description:"disconnect connected containers before removing network"`
+}
+
+// RemoveNetworkResponse represents network remove response
+type RemoveNetworkResponse struct {
+	Removed []string
+}
+
+// ConnectNetworkRequest represents connect container to network request, already connected container is ignored
+type ConnectNetworkRequest struct {
+	Network   string   `required:"true" description:"network name or ID"`
+	Container string   `required:"true" description:"container name or ID"`
+	Aliases   []string `description:"container network aliases"`
+	IPAddress string   `description:"container IPv4 address"`
+}
+
+// ConnectNetworkResponse represents connect container to network response
+type ConnectNetworkResponse struct {
+	NetworkID        string
+	ContainerID      string
+	AlreadyConnected bool
+}
+
+// CreateVolumeRequest represents volume create request, existing volume with the same name is reused
+type CreateVolumeRequest struct {
+	Name       string            `required:"true" description:"volume name"`
+	Driver     string            `description:"volume driver, default local"`
+	DriverOpts map[string]string `description:"volume driver options"`
+	Labels     map[string]string `description:"volume labels, i.e. to prune volumes created by a test session"`
+}
+
+// CreateVolumeResponse represents volume create response
+type CreateVolumeResponse struct {
+	Name       string
+	Mountpoint string
+	Reused     bool
+}
+
+// RemoveVolumeRequest represents volume remove request, missing volumes are ignored
+type RemoveVolumeRequest struct {
+	Name  string
+	Names []string
+	Force bool
+}
+
+// RemoveVolumeResponse represents volume remove response
+type RemoveVolumeResponse struct {
+	Removed []string
+}
+
+// PruneRequest represents unused docker resources prune request
+type PruneRequest struct {
+	Containers bool              `description:"remove stopped containers"`
+	Networks   bool              `description:"remove unused networks"`
+	Volumes    bool              `description:"remove unused volumes, including named ones"`
+	Images     bool              `description:"remove dangling images"`
+	Labels     map[string]string `description:"only prune resources with all matching labels"`
+}
+
+// PruneResponse represents prune response
+type PruneResponse struct {
+	ContainersDeleted []string `json:",omitempty"`
+	NetworksDeleted   []string `json:",omitempty"`
+	VolumesDeleted    []string `json:",omitempty"`
+	ImagesDeleted     []string `json:",omitempty"`
+	SpaceReclaimed    uint64
+}
+
+func (r *CreateNetworkRequest) Validate() error {
+	if r.Name == "" {
+		return errors.New("name was empty")
+	}
+	return nil
+}
+
+// Options returns network create options
+func (r *CreateNetworkRequest) Options() network.CreateOptions {
+	result := network.CreateOptions{
+		Driver:     r.Driver,
+		Internal:   r.Internal,
+		Attachable: r.Attachable,
+		Labels:     r.Labels,
+	}
+	if r.Subnet != "" {
+		result.IPAM = &network.IPAM{Config: []network.IPAMConfig{{Subnet: r.Subnet, Gateway: r.Gateway}}}
+	}
+	return result
+}
+
+func (r *RemoveNetworkRequest) Init() error {
+	if r.Name != "" && len(r.Names) == 0 {
+		r.Names = strings.Split(r.Name, ",")
+	}
+	return nil
+}
+
+func (r *RemoveNetworkRequest) Validate() error {
+	if len(r.Names) == 0 {
+		return errors.New("name was empty")
+	}
+	return nil
+}
+
+func (r *ConnectNetworkRequest) Validate() error {
+	if r.Network == "" {
+		return errors.New("network was empty")
+	}
+	if r.Container == "" {
+		return errors.New("container was empty")
+	}
+	return nil
+}
+
+func (r *CreateVolumeRequest) Validate() error {
+	if r.Name == "" {
+		return errors.New("name was empty")
+	}
+	return nil
+}
+
+func (r *RemoveVolumeRequest) Init() error {
+	if r.Name != "" && len(r.Names) == 0 {
+		r.Names = strings.Split(r.Name, ",")
+	}
+	return nil
+}
+
+func (r *RemoveVolumeRequest) Validate() error {
+	if len(r.Names) == 0 {
+		return errors.New("name was empty")
+	}
+	return nil
+}
+
+func (r *PruneRequest) Validate() error {
+	if !r.Containers && !r.Networks && !r.Volumes && !r.Images {
+		return errors.New("containers, networks, volumes and images were false")
+	}
+	return nil
+}
+
+// Filters returns prune label filters
+func (r *PruneRequest) Filters() filters.Args {
+	result := filters.NewArgs()
+	for key, value := range r.Labels {
+		result.Add("label", key+"="+value)
+	}
+	return result
+}
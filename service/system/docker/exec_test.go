@@ -0,0 +1,76 @@
+package docker
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecRequest_Options(t *testing.T) {
+	request := &ExecRequest{Name: "db", Command: "echo $HOME", Env: map[string]string{"A": "1"}, Workdir: "/tmp", User: "root", Stdin: "abc"}
+	assert.Nil(t, request.Validate())
+	options := request.Options()
+	assert.Equal(t, []string{"/bin/sh", "-c", "echo $HOME"}, options.Cmd)
+	assert.Equal(t, []string{"A=1"}, options.Env)
+	assert.Equal(t, "/tmp", options.WorkingDir)
+	assert.Equal(t, "root", options.User)
+	assert.True(t, options.AttachStdin)
+	assert.True(t, options.AttachStdout)
+	assert.True(t, options.AttachStderr)
+
+	request = &ExecRequest{Name: "db", Cmd: []string{"ls", "-la"}}
+	assert.Equal(t, []string{"ls", "-la"}, request.Options().Cmd)
+	assert.False(t, request.Options().AttachStdin)
+	assert.NotNil(t, (&ExecRequest{Name: "db"}).Validate())
+	assert.NotNil(t, (&ExecRequest{Command: "ls"}).Validate())
+}
+
+func TestValidateExecOutput(t *testing.T) {
+	var useCases = []struct {
+		description string
+		request     *ExecRequest
+		stdout      string
+		hasError    bool
+	}{
+		{description: "no fragments", request: &ExecRequest{}, stdout: "ok"},
+		{description: "error fragment", request: &ExecRequest{Errors: []string{"ERROR"}}, stdout: "ERROR 1045", hasError: true},
+		{description: "success fragment", request: &ExecRequest{Success: []string{"done", "ready"}}, stdout: "server ready"},
+		{description: "missing success fragment", request: &ExecRequest{Success: []string{"done"}}, stdout: "failed", hasError: true},
+	}
+	for _, useCase := range useCases {
+		err := validateExecOutput(useCase.request, useCase.stdout)
+		assert.Equal(t, useCase.hasError, err != nil, useCase.description)
+	}
+}
+
+func TestExecKillScript(t *testing.T) {
+	if _, err := os.Stat("/proc/self/environ"); err != nil {
+		t.Skip("proc file system is not available")
+	}
+	command := exec.Command("sleep", "30")
+	command.Env = append(os.Environ(), execMarkerEnv+"=test-marker")
+	if !assert.Nil(t, command.Start()) {
+		return
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- command.Wait()
+	}()
+	assert.Nil(t, exec.Command("/bin/sh", "-c", execKillScript("other-marker")).Run())
+	select {
+	case <-done:
+		assert.Fail(t, "process without marker should keep running")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.Nil(t, exec.Command("/bin/sh", "-c", execKillScript("test-marker")).Run())
+	select {
+	case err := <-done:
+		assert.NotNil(t, err)
+	case <-time.After(2 * time.Second):
+		_ = command.Process.Kill()
+		assert.Fail(t, "marked process should be killed")
+	}
+}
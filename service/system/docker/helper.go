@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/docker/docker/api/types"
 	"github.com/viant/endly"
+	"github.com/viant/endly/internal/util"
 	"github.com/viant/endly/model/msg"
 	"github.com/viant/toolbox"
 	"os"
@@ -70,3 +71,21 @@ func expandHomeDirectory(location string) string {
 	}
 	return location
 }
+
+// validateExecOutput checks exec stdout against request error and success fragments
+func validateExecOutput(request *ExecRequest, stdout string) error {
+	for _, candidate := range request.Errors {
+		if util.EscapedContains(stdout, candidate) {
+			return fmt.Errorf("encounter error fragment: (%v), command: %v, stdout: %v", candidate, request.Command, stdout)
+		}
+	}
+	if len(request.Success) == 0 {
+		return nil
+	}
+	for _, candidate := range request.Success {
+		if util.EscapedContains(stdout, candidate) {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to match any fragment: '%v', command: %v; stdout: %v", strings.Join(request.Success, ","), request.Command, stdout)
+}
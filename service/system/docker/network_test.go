@@ -0,0 +1,66 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_NetworkVolumeRoutes(t *testing.T) {
+	service := New()
+	var useCases = []struct {
+		action  string
+		request interface{}
+	}{
+		{"networkCreate", &CreateNetworkRequest{}},
+		{"networkRemove", &RemoveNetworkRequest{}},
+		{"networkConnect", &ConnectNetworkRequest{}},
+		{"volumeCreate", &CreateVolumeRequest{}},
+		{"volumeRemove", &RemoveVolumeRequest{}},
+		{"prune", &PruneRequest{}},
+	}
+	for _, useCase := range useCases {
+		route, err := service.Route(useCase.action)
+		if !assert.Nil(t, err, useCase.action) {
+			continue
+		}
+		assert.IsType(t, useCase.request, route.RequestProvider(), useCase.action)
+	}
+	for _, action := range []string{"createNetwork", "removeNetwork", "connectNetwork", "createVolume", "removeVolume"} {
+		_, err := service.Route(action)
+		assert.NotNil(t, err, action)
+	}
+	var count = make(map[string]int)
+	for _, action := range service.Actions() {
+		count[action]++
+	}
+	for action := range nativeActions {
+		assert.Equal(t, 1, count[action], action)
+	}
+}
+
+func TestCreateNetworkRequest_Options(t *testing.T) {
+	request := &CreateNetworkRequest{Name: "e2e", Subnet: "172.28.0.0/16", Gateway: "172.28.0.1", Internal: true, Labels: map[string]string{"session": "1"}}
+	assert.Nil(t, request.Validate())
+	options := request.Options()
+	assert.True(t, options.Internal)
+	assert.Equal(t, map[string]string{"session": "1"}, options.Labels)
+	if assert.NotNil(t, options.IPAM) {
+		assert.Equal(t, "172.28.0.0/16", options.IPAM.Config[0].Subnet)
+		assert.Equal(t, "172.28.0.1", options.IPAM.Config[0].Gateway)
+	}
+	assert.Nil(t, (&CreateNetworkRequest{Name: "e2e"}).Options().IPAM)
+	assert.NotNil(t, (&CreateNetworkRequest{}).Validate())
+}
+
+func TestPruneRequest_Filters(t *testing.T) {
+	request := &PruneRequest{Volumes: true, Labels: map[string]string{"session": "1"}}
+	assert.Nil(t, request.Validate())
+	assert.Equal(t, []string{"session=1"}, request.Filters().Get("label"))
+	assert.NotNil(t, (&PruneRequest{}).Validate())
+
+	remove := &RemoveVolumeRequest{Name: "a,b"}
+	assert.Nil(t, remove.Init())
+	assert.Nil(t, remove.Validate())
+	assert.Equal(t, []string{"a", "b"}, remove.Names)
+}
@@ -0,0 +1,137 @@
+package docker
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadiness_Init(t *testing.T) {
+	readiness := &Readiness{TCP: "3306"}
+	assert.Nil(t, readiness.Init())
+	assert.Nil(t, readiness.Validate())
+	assert.Equal(t, "127.0.0.1:3306", readiness.TCP)
+	assert.Equal(t, 60000, readiness.TimeoutMs)
+	assert.Equal(t, 500, readiness.IntervalMs)
+	assert.Equal(t, 20, readiness.TailLines)
+
+	readiness = &Readiness{}
+	assert.Nil(t, readiness.Init())
+	assert.NotNil(t, readiness.Validate())
+
+	readiness = &Readiness{Log: "ready ("}
+	assert.Nil(t, readiness.Init())
+	assert.NotNil(t, readiness.Validate())
+}
+
+func TestRunRequest_CompletedReadiness(t *testing.T) {
+	request := &RunRequest{Image: "migrate:latest", Readiness: &Readiness{Completed: true}}
+	assert.Nil(t, request.Init())
+	assert.Nil(t, request.Validate())
+	assert.Equal(t, 60000, request.Readiness.TimeoutMs)
+}
+
+func TestService_CheckReadiness(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer listener.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.URL.Path != "/health" {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	newInfo := func(state *types.ContainerState) *types.ContainerJSON {
+		return &types.ContainerJSON{ContainerJSONBase: &types.ContainerJSONBase{ID: "1", State: state}}
+	}
+	running := &types.ContainerState{Status: "running", Running: true}
+	var useCases = []struct {
+		description string
+		readiness   *Readiness
+		state       *types.ContainerState
+		pending     bool
+		hasError    bool
+	}{
+		{
+			description: "exited container",
+			readiness:   &Readiness{TCP: listener.Addr().String()},
+			state:       &types.ContainerState{Status: "exited", ExitCode: 1},
+			hasError:    true,
+		},
+		{
+			description: "completed container",
+			readiness:   &Readiness{Completed: true},
+			state:       &types.ContainerState{Status: "exited", ExitCode: 0},
+		},
+		{
+			description: "failed completed container",
+			readiness:   &Readiness{Completed: true},
+			state:       &types.ContainerState{Status: "exited", ExitCode: 1},
+			hasError:    true,
+		},
+		{
+			description: "created container",
+			readiness:   &Readiness{TCP: listener.Addr().String()},
+			state:       &types.ContainerState{Status: "created"},
+			pending:     true,
+		},
+		{
+			description: "tcp ready",
+			readiness:   &Readiness{TCP: listener.Addr().String()},
+			state:       running,
+		},
+		{
+			description: "http ready",
+			readiness:   &Readiness{HTTP: server.URL + "/health"},
+			state:       running,
+		},
+		{
+			description: "http not ready",
+			readiness:   &Readiness{HTTP: server.URL + "/"},
+			state:       running,
+			pending:     true,
+		},
+		{
+			description: "health starting",
+			readiness:   &Readiness{Health: true},
+			state:       &types.ContainerState{Status: "running", Running: true, Health: &types.Health{Status: container.Starting}},
+			pending:     true,
+		},
+		{
+			description: "healthy",
+			readiness:   &Readiness{Health: true},
+			state:       &types.ContainerState{Status: "running", Running: true, Health: &types.Health{Status: container.Healthy}},
+		},
+		{
+			description: "unhealthy",
+			readiness:   &Readiness{Health: true},
+			state:       &types.ContainerState{Status: "running", Running: true, Health: &types.Health{Status: container.Unhealthy}},
+			hasError:    true,
+		},
+		{
+			description: "no healthcheck",
+			readiness:   &Readiness{Health: true},
+			state:       running,
+			hasError:    true,
+		},
+	}
+	srv := &service{}
+	for _, useCase := range useCases {
+		assert.Nil(t, useCase.readiness.Init(), useCase.description)
+		pending, err := srv.checkReadiness(nil, newInfo(useCase.state), useCase.readiness, nil, http.DefaultClient)
+		if useCase.hasError {
+			assert.NotNil(t, err, useCase.description)
+			continue
+		}
+		assert.Nil(t, err, useCase.description)
+		assert.Equal(t, useCase.pending, pending != "", useCase.description)
+	}
+}
@@ -6,16 +6,27 @@ import (
 	"fmt"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
 	imgt "github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/versions"
+	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/go-errors/errors"
 	"github.com/viant/endly"
 	"github.com/viant/endly/model/location"
 	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
 	"io"
 	"io/ioutil"
 	"log"
+	"net"
+	"net/http"
 	"path"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -23,6 +34,8 @@ import (
 const (
 	//ServiceID aws Simple Queue Service ID.
 	ServiceID = "docker"
+	//execMarkerEnv exec environment variable used to find processes of timed out exec
+	execMarkerEnv = "ENDLY_EXEC_ID"
 )
 
 // no operation service
@@ -109,13 +122,18 @@ func (s *service) run(context *endly.Context, request *RunRequest) (*RunResponse
 	if containerInfo != nil {
 		if request.Reuse {
 			response.ContainerID = containerInfo.ID
-			if IsContainerUp(containerInfo) {
-				return response, nil
+			if !IsContainerUp(containerInfo) {
+				if _, err := s.start(context, &StartRequest{
+					IDs: []string{containerInfo.ID},
+				}); err != nil {
+					return response, err
+				}
 			}
-			_, err := s.start(context, &StartRequest{
-				IDs: []string{containerInfo.ID},
-			})
-			return response, err
+			if request.Readiness != nil {
+				_, err = s.waitReady(context, containerInfo.ID, request.Readiness)
+				return response, err
+			}
+			return response, nil
 		}
 
 		if _, err := s.remove(context, &RemoveRequest{
@@ -174,6 +192,11 @@ func (s *service) run(context *endly.Context, request *RunRequest) (*RunResponse
 			}
 		}
 	}
+	if request.Readiness != nil && !request.Block {
+		if _, err := s.waitReady(context, createResponse.ID, request.Readiness); err != nil {
+			return nil, err
+		}
+	}
 	status, err := s.status(context, startRequest.AsStatusRequest())
 	if err != nil {
 		return nil, err
@@ -194,6 +217,107 @@ func (s *service) run(context *endly.Context, request *RunRequest) (*RunResponse
 	return response, nil
 }
 
+// waitReady waits until all readiness checks pass, error includes container last log lines
+func (s *service) waitReady(context *endly.Context, containerID string, readiness *Readiness) (*types.ContainerState, error) {
+	deadline := time.Now().Add(time.Duration(readiness.TimeoutMs) * time.Millisecond)
+	interval := time.Duration(readiness.IntervalMs) * time.Millisecond
+	var logExpr *regexp.Regexp
+	if readiness.Log != "" {
+		logExpr = regexp.MustCompile(readiness.Log)
+	}
+	httpClient := &http.Client{Timeout: interval + time.Second}
+	for {
+		var info types.ContainerJSON
+		if err := runAdapter(context, &ContainerInspectRequest{ContainerID: containerID}, &info); err != nil {
+			return nil, err
+		}
+		pending, err := s.checkReadiness(context, &info, readiness, logExpr, httpClient)
+		if err == nil && pending == "" {
+			return info.State, nil
+		}
+		if err == nil && time.Now().After(deadline) {
+			err = fmt.Errorf("timeout after %vms, %v", readiness.TimeoutMs, pending)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("container %v is not ready: %v\nlast %v log lines:\n%v", strings.TrimPrefix(info.Name, "/"), err, readiness.TailLines, s.containerLogs(context, containerID, strconv.Itoa(readiness.TailLines)))
+		}
+		time.Sleep(interval)
+	}
+}
+
+// checkReadiness returns pending check description, or error if container can not become ready
+func (s *service) checkReadiness(context *endly.Context, info *types.ContainerJSON, readiness *Readiness, logExpr *regexp.Regexp, httpClient *http.Client) (string, error) {
+	state := info.State
+	if state == nil {
+		return "container state is unknown", nil
+	}
+	if state.Status == "exited" && state.ExitCode == 0 && readiness.Completed {
+		return "", nil
+	}
+	if state.Status == "exited" || state.Status == "dead" {
+		return "", fmt.Errorf("container exited with code %v", state.ExitCode)
+	}
+	if !state.Running {
+		return "container is " + state.Status, nil
+	}
+	if readiness.Health {
+		if state.Health == nil {
+			return "", fmt.Errorf("container has no HEALTHCHECK")
+		}
+		switch state.Health.Status {
+		case container.Healthy:
+		case container.Unhealthy:
+			return "", fmt.Errorf("container is unhealthy")
+		default:
+			return "health status: " + state.Health.Status, nil
+		}
+	}
+	if readiness.TCP != "" {
+		conn, err := net.DialTimeout("tcp", readiness.TCP, time.Duration(readiness.IntervalMs)*time.Millisecond)
+		if err != nil {
+			return fmt.Sprintf("tcp %v: %v", readiness.TCP, err), nil
+		}
+		_ = conn.Close()
+	}
+	if readiness.HTTP != "" {
+		response, err := httpClient.Get(readiness.HTTP)
+		if err != nil {
+			return fmt.Sprintf("http %v: %v", readiness.HTTP, err), nil
+		}
+		_, _ = io.Copy(ioutil.Discard, response.Body)
+		_ = response.Body.Close()
+		if response.StatusCode < 200 || response.StatusCode > 299 {
+			return fmt.Sprintf("http %v: status code %v", readiness.HTTP, response.StatusCode), nil
+		}
+	}
+	if logExpr != nil && !logExpr.MatchString(s.containerLogs(context, info.ID, "all")) {
+		return fmt.Sprintf("log did not match: %v", readiness.Log), nil
+	}
+	return "", nil
+}
+
+// containerLogs returns container stdout and stderr, tail is number of last lines or all
+func (s *service) containerLogs(context *endly.Context, containerID string, tail string) string {
+	logRequest := &ContainerLogsRequest{Container: containerID}
+	logRequest.ShowStdout = true
+	logRequest.ShowStderr = true
+	logRequest.Tail = tail
+	var reader io.ReadCloser
+	if err := runAdapter(context, logRequest, &reader); err != nil {
+		return ""
+	}
+	defer reader.Close()
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return ""
+	}
+	var output = new(bytes.Buffer)
+	if _, err = stdcopy.StdCopy(output, output, bytes.NewReader(data)); err != nil { //container with TTY logs are not multiplexed
+		return string(data)
+	}
+	return output.String()
+}
+
 func (s *service) pull(context *endly.Context, request *PullRequest) (*PullResponse, error) {
 	err := request.Init()
 	if err != nil {
@@ -562,6 +686,476 @@ func (s *service) logs(context *endly.Context, request *LogsRequest) (*LogsRespo
 	return response, nil
 }
 
+// exec runs a command inside running container, stdin is passed to the command when specified
+func (s *service) exec(context *endly.Context, request *ExecRequest) (*ExecResponse, error) {
+	status, err := s.status(context, &StatusRequest{Name: request.Name})
+	if err != nil {
+		return nil, err
+	}
+	containerID := request.Name
+	if len(status.Containers) > 0 {
+		containerID = status.Containers[0].ID
+	}
+	response := &ExecResponse{ContainerID: containerID, Data: data.NewMap()}
+	options := request.Options()
+	marker := fmt.Sprintf("%v", time.Now().UnixNano())
+	if request.TimeoutMs > 0 {
+		options.Env = append(options.Env, execMarkerEnv+"="+marker)
+	}
+	var created container.ExecCreateResponse
+	if err = runAdapter(context, &ContainerExecCreateRequest{Container: containerID, Config: options}, &created); err != nil {
+		return nil, fmt.Errorf("failed to create exec in %v: %v", request.Name, err)
+	}
+	var attached types.HijackedResponse
+	if err = runAdapter(context, &ContainerExecAttachRequest{ExecID: created.ID, Config: container.ExecStartOptions{Tty: request.Tty}}, &attached); err != nil {
+		return nil, fmt.Errorf("failed to start exec in %v: %v", request.Name, err)
+	}
+	defer attached.Close()
+	if request.Stdin != "" {
+		go func() {
+			_, _ = io.WriteString(attached.Conn, request.Stdin)
+			_ = attached.CloseWrite()
+		}()
+	}
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	done := make(chan error, 1)
+	go func() {
+		if request.Tty {
+			_, err := io.Copy(stdout, attached.Reader)
+			done <- err
+			return
+		}
+		_, err := stdcopy.StdCopy(stdout, stderr, attached.Reader)
+		done <- err
+	}()
+	var timeout <-chan time.Time
+	if request.TimeoutMs > 0 {
+		timeout = time.After(time.Duration(request.TimeoutMs) * time.Millisecond)
+	}
+	select {
+	case err = <-done:
+		if err != nil {
+			return nil, fmt.Errorf("failed to read exec output: %v", err)
+		}
+	case <-timeout:
+		_ = attached.Conn.Close()
+		<-done
+		if e := s.killExec(context, containerID, marker); e != nil {
+			return nil, fmt.Errorf("exec timeout after %vms, command: %v, stdout: %v, failed to terminate: %v", request.TimeoutMs, options.Cmd, stdout.String(), e)
+		}
+		return nil, fmt.Errorf("exec timeout after %vms, command: %v, stdout: %v", request.TimeoutMs, options.Cmd, stdout.String())
+	}
+	response.Stdout, response.Stderr = stdout.String(), stderr.String()
+	var inspected container.ExecInspect
+	if err = runAdapter(context, &ContainerExecInspectRequest{ExecID: created.ID}, &inspected); err != nil {
+		return nil, err
+	}
+	response.ExitCode = inspected.ExitCode
+	publishEvent(context, "exec", response)
+	if request.CheckError && response.ExitCode != 0 {
+		return response, fmt.Errorf("exit code: %v, command: %v, stderr: %v", response.ExitCode, options.Cmd, response.Stderr)
+	}
+	if err = validateExecOutput(request, response.Stdout); err != nil {
+		return response, err
+	}
+	err = request.Extract.Extract(context, response.Data, strings.Split(response.Stdout, "\n")...)
+	return response, err
+}
+
+// killExec terminates processes of timed out exec, docker API can not stop exec process,
+// so processes are matched inside the container by exec marker environment variable
+func (s *service) killExec(context *endly.Context, containerID, marker string) error {
+	config := container.ExecOptions{Cmd: []string{"/bin/sh", "-c", execKillScript(marker)}, AttachStdout: true, AttachStderr: true}
+	var created container.ExecCreateResponse
+	if err := runAdapter(context, &ContainerExecCreateRequest{Container: containerID, Config: config}, &created); err != nil {
+		return err
+	}
+	var attached types.HijackedResponse
+	if err := runAdapter(context, &ContainerExecAttachRequest{ExecID: created.ID, Config: container.ExecStartOptions{}}, &attached); err != nil {
+		return err
+	}
+	defer attached.Close()
+	_, err := io.Copy(io.Discard, attached.Reader)
+	return err
+}
+
+// execKillScript returns shell script killing container processes with supplied exec marker
+func execKillScript(marker string) string {
+	return fmt.Sprintf(`for p in /proc/[0-9]*; do tr '\0' '\n' < $p/environ 2>/dev/null | grep -qx '%v=%v' && kill -9 ${p#/proc/} 2>/dev/null; done; true`, execMarkerEnv, marker)
+}
+
+// loadStack returns project name and stack services from compose file merged with request services
+func (s *service) loadStack(context *endly.Context, project, composeFile string, services map[string]*StackService) (string, map[string]*StackService, error) {
+	var result = make(map[string]*StackService)
+	var composeProject, composeDirectory string
+	if composeFile != "" {
+		resource := location.NewResource(context.Expand(composeFile))
+		content, err := resource.DownloadText()
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to load compose file %v, %v", resource.URL, err)
+		}
+		composeDirectory = path.Dir(resource.Path())
+		if composeProject, result, err = decodeComposeFile([]byte(context.Expand(content)), composeDirectory); err != nil {
+			return "", nil, err
+		}
+	}
+	for name, service := range services {
+		result[name] = service
+	}
+	return projectName(project, composeProject, path.Base(composeDirectory)), result, nil
+}
+
+func (s *service) up(context *endly.Context, request *UpRequest) (*UpResponse, error) {
+	project, services, err := s.loadStack(context, request.Project, request.ComposeFile, request.Services)
+	if err != nil {
+		return nil, err
+	}
+	order, err := stackOrder(services)
+	if err != nil {
+		return nil, err
+	}
+	response := &UpResponse{Project: project, Network: project + "_default"}
+	if err = s.createStackNetwork(context, project, response.Network); err != nil {
+		return nil, err
+	}
+	var containers = make(map[string]string)
+	var readiness = make(map[string]*Readiness)
+	for _, name := range order {
+		service := services[name]
+		for _, dependency := range service.DependsOn {
+			if _, err = s.waitReady(context, containers[dependency], readiness[dependency]); err != nil {
+				return nil, fmt.Errorf("service %v dependency %v: %v", name, dependency, err)
+			}
+		}
+		runRequest, err := s.stackRunRequest(context, project, response.Network, name, service)
+		if err != nil {
+			return nil, fmt.Errorf("service %v: %v", name, err)
+		}
+		runResponse, err := s.run(context, runRequest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to start service %v: %v", name, err)
+		}
+		containers[name] = runResponse.ContainerID
+		if readiness[name], err = s.stackReadiness(context, runRequest, runResponse.ContainerID, request.WaitTimeMs); err != nil {
+			return nil, fmt.Errorf("service %v: %v", name, err)
+		}
+		response.Services = append(response.Services, &StackServiceInfo{Name: name, ContainerName: runRequest.Name, ContainerID: runResponse.ContainerID})
+	}
+	for _, info := range response.Services {
+		state, err := s.waitReady(context, info.ContainerID, readiness[info.Name])
+		if err != nil {
+			return response, fmt.Errorf("service %v: %v", info.Name, err)
+		}
+		info.Status = state.Status
+		if state.Health != nil {
+			info.Health = state.Health.Status
+		}
+	}
+	return response, nil
+}
+
+// stackReadiness returns service readiness, by default service is ready when its health check passes,
+// when running if no health check is defined, or when it has exited with 0 code
+func (s *service) stackReadiness(context *endly.Context, request *RunRequest, containerID string, timeoutMs int) (*Readiness, error) {
+	if request.Readiness != nil {
+		return request.Readiness, nil
+	}
+	var info types.ContainerJSON
+	if err := runAdapter(context, &ContainerInspectRequest{ContainerID: containerID}, &info); err != nil {
+		return nil, err
+	}
+	result := &Readiness{Completed: true, TimeoutMs: timeoutMs}
+	if info.Config != nil && info.Config.Healthcheck != nil { //includes image HEALTHCHECK
+		test := info.Config.Healthcheck.Test
+		result.Health = len(test) > 0 && test[0] != "NONE"
+	}
+	return result, result.Init()
+}
+
+// stackRunRequest returns initialised run request for stack service
+func (s *service) stackRunRequest(context *endly.Context, project, networkName, name string, service *StackService) (*RunRequest, error) {
+	if service.RunRequest == nil {
+		return nil, fmt.Errorf("image was empty")
+	}
+	request := service.RunRequest.clone()
+	if request.Name == "" {
+		request.Name = project + "_" + name
+	}
+	if err := request.Init(); err != nil {
+		return nil, err
+	}
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	if request.Config.Labels == nil {
+		request.Config.Labels = make(map[string]string)
+	}
+	request.Config.Labels[StackLabel] = project
+	request.Config.Labels[StackServiceLabel] = name
+	if service.HealthCheck != nil {
+		request.Config.Healthcheck = service.HealthCheck.Config()
+	}
+	request.HostConfig.NetworkMode = container.NetworkMode(networkName)
+	request.NetworkingConfig = &network.NetworkingConfig{
+		EndpointsConfig: map[string]*network.EndpointSettings{
+			networkName: {Aliases: []string{name}},
+		},
+	}
+	for volumeName, target := range service.Volumes {
+		volumeName = project + "_" + volumeName
+		createRequest := &VolumeCreateRequest{CreateOptions: volume.CreateOptions{Name: volumeName, Labels: map[string]string{StackLabel: project}}}
+		if err := runAdapter(context, createRequest, nil); err != nil {
+			return nil, fmt.Errorf("failed to create volume %v: %v", volumeName, err)
+		}
+		target, readOnly := mountTarget(target)
+		request.HostConfig.Mounts = append(request.HostConfig.Mounts, mount.Mount{Type: mount.TypeVolume, Source: volumeName, Target: target, ReadOnly: readOnly})
+	}
+	return request, nil
+}
+
+func (s *service) createStackNetwork(context *endly.Context, project, networkName string) error {
+	_, err := s.createNetwork(context, &CreateNetworkRequest{Name: networkName, Labels: map[string]string{StackLabel: project}})
+	return err
+}
+
+// networkByName returns network with matching name or ID, or nil if network does not exist
+func (s *service) networkByName(context *endly.Context, name string) (*network.Summary, error) {
+	var networks = make([]network.Summary, 0)
+	listRequest := &NetworkListRequest{ListOptions: network.ListOptions{Filters: filters.NewArgs(filters.Arg("name", name))}}
+	if err := runAdapter(context, listRequest, &networks); err != nil {
+		return nil, err
+	}
+	for i, candidate := range networks {
+		if candidate.Name == name || candidate.ID == name {
+			return &networks[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// volumeByName returns volume with matching name, or nil if volume does not exist
+func (s *service) volumeByName(context *endly.Context, name string) (*volume.Volume, error) {
+	var volumes volume.ListResponse
+	listRequest := &VolumeListRequest{ListOptions: volume.ListOptions{Filters: filters.NewArgs(filters.Arg("name", name))}}
+	if err := runAdapter(context, listRequest, &volumes); err != nil {
+		return nil, err
+	}
+	for _, candidate := range volumes.Volumes {
+		if candidate.Name == name {
+			return candidate, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *service) createNetwork(context *endly.Context, request *CreateNetworkRequest) (*CreateNetworkResponse, error) {
+	response := &CreateNetworkResponse{Name: request.Name}
+	existing, err := s.networkByName(context, request.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		response.NetworkID = existing.ID
+		response.Reused = true
+		return response, nil
+	}
+	var created network.CreateResponse
+	if err = runAdapter(context, &NetworkCreateRequest{Name: request.Name, CreateOptions: request.Options()}, &created); err != nil {
+		return nil, fmt.Errorf("failed to create network %v: %v", request.Name, err)
+	}
+	response.NetworkID = created.ID
+	return response, nil
+}
+
+func (s *service) removeNetwork(context *endly.Context, request *RemoveNetworkRequest) (*RemoveNetworkResponse, error) {
+	response := &RemoveNetworkResponse{Removed: make([]string, 0)}
+	for _, name := range request.Names {
+		existing, err := s.networkByName(context, name)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			continue
+		}
+		if request.Force {
+			var info network.Inspect
+			if err = runAdapter(context, &NetworkInspectRequest{NetworkID: existing.ID}, &info); err != nil {
+				return nil, err
+			}
+			for containerID := range info.Containers {
+				if err = runAdapter(context, &NetworkDisconnectRequest{NetworkID: existing.ID, ContainerID: containerID, Force: true}, nil); err != nil {
+					return nil, fmt.Errorf("failed to disconnect %v from network %v: %v", containerID, name, err)
+				}
+			}
+		}
+		if err = runAdapter(context, &NetworkRemoveRequest{NetworkID: existing.ID}, nil); err != nil {
+			return nil, fmt.Errorf("failed to remove network %v: %v", name, err)
+		}
+		response.Removed = append(response.Removed, existing.Name)
+	}
+	return response, nil
+}
+
+func (s *service) connectNetwork(context *endly.Context, request *ConnectNetworkRequest) (*ConnectNetworkResponse, error) {
+	existing, err := s.networkByName(context, request.Network)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("network %v does not exist", request.Network)
+	}
+	var containerInfo types.ContainerJSON
+	if err = runAdapter(context, &ContainerInspectRequest{ContainerID: request.Container}, &containerInfo); err != nil {
+		return nil, err
+	}
+	response := &ConnectNetworkResponse{NetworkID: existing.ID, ContainerID: containerInfo.ID}
+	if containerInfo.NetworkSettings != nil {
+		if _, ok := containerInfo.NetworkSettings.Networks[existing.Name]; ok {
+			response.AlreadyConnected = true
+			return response, nil
+		}
+	}
+	settings := &network.EndpointSettings{Aliases: request.Aliases}
+	if request.IPAddress != "" {
+		settings.IPAMConfig = &network.EndpointIPAMConfig{IPv4Address: request.IPAddress}
+	}
+	if err = runAdapter(context, &NetworkConnectRequest{NetworkID: existing.ID, ContainerID: containerInfo.ID, Config: settings}, nil); err != nil {
+		return nil, fmt.Errorf("failed to connect %v to network %v: %v", request.Container, request.Network, err)
+	}
+	return response, nil
+}
+
+func (s *service) createVolume(context *endly.Context, request *CreateVolumeRequest) (*CreateVolumeResponse, error) {
+	response := &CreateVolumeResponse{Name: request.Name}
+	existing, err := s.volumeByName(context, request.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		response.Mountpoint = existing.Mountpoint
+		response.Reused = true
+		return response, nil
+	}
+	var created volume.Volume
+	createRequest := &VolumeCreateRequest{CreateOptions: volume.CreateOptions{Name: request.Name, Driver: request.Driver, DriverOpts: request.DriverOpts, Labels: request.Labels}}
+	if err = runAdapter(context, createRequest, &created); err != nil {
+		return nil, fmt.Errorf("failed to create volume %v: %v", request.Name, err)
+	}
+	response.Mountpoint = created.Mountpoint
+	return response, nil
+}
+
+func (s *service) removeVolume(context *endly.Context, request *RemoveVolumeRequest) (*RemoveVolumeResponse, error) {
+	response := &RemoveVolumeResponse{Removed: make([]string, 0)}
+	for _, name := range request.Names {
+		existing, err := s.volumeByName(context, name)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			continue
+		}
+		if err = runAdapter(context, &VolumeRemoveRequest{VolumeID: existing.Name, Force: request.Force}, nil); err != nil {
+			return nil, fmt.Errorf("failed to remove volume %v: %v", name, err)
+		}
+		response.Removed = append(response.Removed, existing.Name)
+	}
+	return response, nil
+}
+
+func (s *service) prune(context *endly.Context, request *PruneRequest) (*PruneResponse, error) {
+	response := &PruneResponse{}
+	if request.Containers {
+		var report container.PruneReport
+		if err := runAdapter(context, &ContainersPruneRequest{PruneFilters: request.Filters()}, &report); err != nil {
+			return nil, fmt.Errorf("failed to prune containers: %v", err)
+		}
+		response.ContainersDeleted = report.ContainersDeleted
+		response.SpaceReclaimed += report.SpaceReclaimed
+	}
+	if request.Networks {
+		var report network.PruneReport
+		if err := runAdapter(context, &NetworksPruneRequest{PruneFilters: request.Filters()}, &report); err != nil {
+			return nil, fmt.Errorf("failed to prune networks: %v", err)
+		}
+		response.NetworksDeleted = report.NetworksDeleted
+	}
+	if request.Volumes {
+		ctxClient, err := GetCtxClient(context)
+		if err != nil {
+			return nil, err
+		}
+		pruneFilters := request.Filters()
+		if versions.GreaterThanOrEqualTo(ctxClient.Client.ClientVersion(), "1.42") { //since 1.42 only anonymous volumes are pruned by default
+			pruneFilters.Add("all", "true")
+		}
+		var report volume.PruneReport
+		if err = runAdapter(context, &VolumesPruneRequest{PruneFilters: pruneFilters}, &report); err != nil {
+			return nil, fmt.Errorf("failed to prune volumes: %v", err)
+		}
+		response.VolumesDeleted = report.VolumesDeleted
+		response.SpaceReclaimed += report.SpaceReclaimed
+	}
+	if request.Images {
+		var report imgt.PruneReport
+		if err := runAdapter(context, &ImagesPruneRequest{PruneFilters: request.Filters()}, &report); err != nil {
+			return nil, fmt.Errorf("failed to prune images: %v", err)
+		}
+		for _, deleted := range report.ImagesDeleted {
+			if deleted.Deleted != "" {
+				response.ImagesDeleted = append(response.ImagesDeleted, deleted.Deleted)
+			}
+		}
+		response.SpaceReclaimed += report.SpaceReclaimed
+	}
+	return response, nil
+}
+
+func (s *service) down(context *endly.Context, request *DownRequest) (*DownResponse, error) {
+	project, _, err := s.loadStack(context, request.Project, request.ComposeFile, nil)
+	if err != nil {
+		return nil, err
+	}
+	response := &DownResponse{Project: project}
+	byLabel := filters.NewArgs(filters.Arg("label", StackLabel+"="+project))
+	var containers = make([]types.Container, 0)
+	if err = runAdapter(context, &ContainerListRequest{ListOptions: container.ListOptions{All: true, Filters: byLabel}}, &containers); err != nil {
+		return nil, err
+	}
+	for _, candidate := range containers {
+		if _, err = s.remove(context, &RemoveRequest{IDs: []string{candidate.ID}}); err != nil {
+			return nil, err
+		}
+		for _, name := range candidate.Names {
+			response.Containers = append(response.Containers, strings.TrimPrefix(name, "/"))
+		}
+	}
+	var networks = make([]network.Summary, 0)
+	if err = runAdapter(context, &NetworkListRequest{ListOptions: network.ListOptions{Filters: byLabel}}, &networks); err != nil {
+		return nil, err
+	}
+	for _, candidate := range networks {
+		if err = runAdapter(context, &NetworkRemoveRequest{NetworkID: candidate.ID}, nil); err != nil {
+			return nil, fmt.Errorf("failed to remove network %v: %v", candidate.Name, err)
+		}
+		response.Network = candidate.Name
+	}
+	if !request.Volumes {
+		return response, nil
+	}
+	var volumes volume.ListResponse
+	if err = runAdapter(context, &VolumeListRequest{ListOptions: volume.ListOptions{Filters: byLabel}}, &volumes); err != nil {
+		return nil, err
+	}
+	for _, candidate := range volumes.Volumes {
+		if err = runAdapter(context, &VolumeRemoveRequest{VolumeID: candidate.Name, Force: true}, nil); err != nil {
+			return nil, fmt.Errorf("failed to remove volume %v: %v", candidate.Name, err)
+		}
+		response.Volumes = append(response.Volumes, candidate.Name)
+	}
+	return response, nil
+}
+
 func (s *service) registerRoutes() {
 	dockerClient := &client.Client{}
 	routes, err := BuildRoutes(dockerClient, GetCtxClient)
@@ -570,6 +1164,9 @@ func (s *service) registerRoutes() {
 		return
 	}
 	for _, route := range routes {
+		if nativeActions[route.Action] { //replaced by idempotent actions below
+			continue
+		}
 		route.OnRawRequest = initClient
 		s.Register(route)
 	}
@@ -622,6 +1219,190 @@ func (s *service) registerRoutes() {
 		},
 	})
 
+	s.Register(&endly.Route{
+		Action:       "up",
+		OnRawRequest: initClient,
+		RequestInfo: &endly.ActionInfo{
+			Description: "start compose style stack services in dependency order on a dedicated network and wait for health",
+		},
+		RequestProvider: func() interface{} {
+			return &UpRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &UpResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*UpRequest); ok {
+				response, err := s.up(context, req)
+				if err == nil {
+					publishEvent(context, "up", response)
+				}
+				return response, err
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action:       "down",
+		OnRawRequest: initClient,
+		RequestInfo: &endly.ActionInfo{
+			Description: "remove compose style stack containers, network and optionally volumes",
+		},
+		RequestProvider: func() interface{} {
+			return &DownRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &DownResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*DownRequest); ok {
+				return s.down(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action:       "exec",
+		OnRawRequest: initClient,
+		RequestInfo: &endly.ActionInfo{
+			Description: "run command inside running container",
+		},
+		RequestProvider: func() interface{} {
+			return &ExecRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &ExecResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*ExecRequest); ok {
+				return s.exec(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action:       "networkCreate",
+		OnRawRequest: initClient,
+		RequestInfo: &endly.ActionInfo{
+			Description: "create network if it does not exist",
+		},
+		RequestProvider: func() interface{} {
+			return &CreateNetworkRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &CreateNetworkResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*CreateNetworkRequest); ok {
+				return s.createNetwork(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action:       "networkRemove",
+		OnRawRequest: initClient,
+		RequestInfo: &endly.ActionInfo{
+			Description: "remove networks if exist",
+		},
+		RequestProvider: func() interface{} {
+			return &RemoveNetworkRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &RemoveNetworkResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*RemoveNetworkRequest); ok {
+				return s.removeNetwork(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action:       "networkConnect",
+		OnRawRequest: initClient,
+		RequestInfo: &endly.ActionInfo{
+			Description: "connect container to network if not connected",
+		},
+		RequestProvider: func() interface{} {
+			return &ConnectNetworkRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &ConnectNetworkResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*ConnectNetworkRequest); ok {
+				return s.connectNetwork(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action:       "volumeCreate",
+		OnRawRequest: initClient,
+		RequestInfo: &endly.ActionInfo{
+			Description: "create volume if it does not exist",
+		},
+		RequestProvider: func() interface{} {
+			return &CreateVolumeRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &CreateVolumeResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*CreateVolumeRequest); ok {
+				return s.createVolume(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action:       "volumeRemove",
+		OnRawRequest: initClient,
+		RequestInfo: &endly.ActionInfo{
+			Description: "remove volumes if exist",
+		},
+		RequestProvider: func() interface{} {
+			return &RemoveVolumeRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &RemoveVolumeResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*RemoveVolumeRequest); ok {
+				return s.removeVolume(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
+	s.Register(&endly.Route{
+		Action:       "prune",
+		OnRawRequest: initClient,
+		RequestInfo: &endly.ActionInfo{
+			Description: "remove unused containers, networks, volumes or images",
+		},
+		RequestProvider: func() interface{} {
+			return &PruneRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &PruneResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*PruneRequest); ok {
+				return s.prune(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+
 	s.Register(&endly.Route{
 		Action: "stop",
 		RequestInfo: &endly.ActionInfo{
@@ -874,6 +1655,15 @@ func (s *service) registerRoutes() {
 	})
 }
 
+// nativeActions represents generated docker client proxy actions replaced by idempotent actions
+var nativeActions = map[string]bool{
+	"networkCreate":  true,
+	"networkRemove":  true,
+	"networkConnect": true,
+	"volumeCreate":   true,
+	"volumeRemove":   true,
+}
+
 // New creates a new Docker service.
 func New() endly.Service {
 	var result = &service{
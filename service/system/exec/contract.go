@@ -37,6 +37,9 @@ type Options struct {
 	Secrets     secret.Secrets    `description:"secrets map see https://github.com/viant/toolbox/tree/master/secret"`
 	CheckError  bool              `description:"check after command execution if status is <> 0, then throws error"`
 	AutoSudo    bool              `description:"when this flag is set, in case of permission denied error for non root user retry command with sudo"`
+	Stream      bool              `description:"publish command output lines as they arrive as msg stream events, CLI renders them live"`
+	StreamTail  int               `description:"max number of last command output lines kept on the CLI screen, 0 - no limit"`
+	StreamFile  string            `description:"file where full command output stream is written, relative path is resolved with session log directory"`
 }
 
 // DefaultOptions creates a default execution options
@@ -45,8 +45,12 @@ func (e *StdoutEvent) Messages() []*msg.Message {
 
 // NewStdoutEvent crates a new execution start event value
 func NewStdoutEvent(sessionID string, stdout string, err error) *StdoutEvent {
-	return &StdoutEvent{
+	result := &StdoutEvent{
 		SessionID: sessionID,
 		Stdout:    stdout,
 	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
 }
@@ -363,12 +363,25 @@ func (s *execService) executeCommand(context *endly.Context, session *model.Sess
 	}
 	s.Begin(context, NewSdtinEvent(session.ID, securedCommand))
 
+	var stream *outputStream
+	if options.Stream || options.StreamFile != "" {
+		if stream, err = newOutputStream(context, session.ID, options, securedCommand); err != nil {
+			return err
+		}
+		defer stream.Close()
+	}
 	commandRetry := false
 	listener = func(stdout string, hasMore bool) {
 		if !commandRetry && request.AutoSudo && !util.IsPermitted(stdout) {
 			return
 		}
-		if stdout != "" {
+		if stdout == "" {
+			return
+		}
+		if stream != nil {
+			stream.Write(stdout)
+		}
+		if stream == nil || !stream.publish {
 			context.Publish(NewStdoutEvent(session.ID, stdout, err))
 		}
 	}
@@ -0,0 +1,85 @@
+package exec
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/msg"
+)
+
+// outputStream publishes complete command output lines as stream events and writes full output to optional stream file
+type outputStream struct {
+	context   *endly.Context
+	source    string
+	publish   bool
+	tailLines int
+	pending   string
+	file      *os.File
+}
+
+// Write handles command output fragment
+func (s *outputStream) Write(fragment string) {
+	if s.file != nil {
+		_, _ = s.file.WriteString(fragment)
+	}
+	if !s.publish {
+		return
+	}
+	text := s.pending + fragment
+	index := strings.LastIndex(text, "\n")
+	if index == -1 {
+		s.pending = text
+		return
+	}
+	s.pending = text[index+1:]
+	s.context.Publish(msg.NewStreamEvent(s.source, text[:index+1], s.tailLines))
+}
+
+// Close publishes pending incomplete line and closes stream file
+func (s *outputStream) Close() error {
+	if s.publish && s.pending != "" {
+		s.context.Publish(msg.NewStreamEvent(s.source, s.pending+"\n", s.tailLines))
+		s.pending = ""
+	}
+	if s.file == nil {
+		return nil
+	}
+	_, _ = s.file.WriteString("\n")
+	return s.file.Close()
+}
+
+// newOutputStream creates command output stream, command is written to the stream file before its output
+func newOutputStream(context *endly.Context, sessionID string, options *Options, command string) (*outputStream, error) {
+	result := &outputStream{
+		context:   context,
+		source:    sessionID,
+		publish:   options.Stream,
+		tailLines: options.StreamTail,
+	}
+	if options.StreamFile == "" {
+		return result, nil
+	}
+	filename := streamFilename(context, options.StreamFile)
+	if err := os.MkdirAll(path.Dir(filename), 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open stream file %v, %w", filename, err)
+	}
+	result.file = file
+	_, err = file.WriteString(fmt.Sprintf("%v$ %v\n", sessionID, command))
+	return result, err
+}
+
+// streamFilename returns stream file name, relative name is resolved with session log directory if logging is enabled
+func streamFilename(context *endly.Context, name string) string {
+	name = context.Expand(name)
+	if path.IsAbs(name) || context.LogDirectory == "" {
+		return name
+	}
+	return path.Join(context.LogDirectory, name)
+}
@@ -0,0 +1,41 @@
+package exec
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/msg"
+)
+
+func TestOutputStream_Write(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(nil)
+	context.LogDirectory = path.Join(os.TempDir(), "endly_stream_test")
+	defer os.RemoveAll(context.LogDirectory)
+	var events = make([]*msg.StreamEvent, 0)
+	context.Listener = func(event msg.Event) {
+		if value, ok := event.Value().(*msg.StreamEvent); ok {
+			events = append(events, value)
+		}
+	}
+	stream, err := newOutputStream(context, "localhost", &Options{Stream: true, StreamTail: 2, StreamFile: "build.log"}, "make build")
+	if !assert.Nil(t, err) {
+		return
+	}
+	stream.Write("compiling a")
+	assert.Equal(t, 0, len(events))
+	stream.Write("\ncompiling b\ncompiling c\nlinking")
+	assert.Nil(t, stream.Close())
+	if assert.Equal(t, 2, len(events)) {
+		assert.Equal(t, "compiling a\ncompiling b\ncompiling c\n", events[0].Output)
+		assert.Equal(t, 2, events[0].Tail)
+		assert.Equal(t, "linking\n", events[1].Output)
+	}
+	content, err := ioutil.ReadFile(path.Join(context.LogDirectory, "build.log"))
+	assert.Nil(t, err)
+	assert.Equal(t, "localhost$ make build\ncompiling a\ncompiling b\ncompiling c\nlinking\n", string(content))
+}
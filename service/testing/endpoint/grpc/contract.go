@@ -0,0 +1,139 @@
+package grpc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/viant/endly/service/testing/validator"
+)
+
+// ListenRequest represents gRPC endpoint listen request
+type ListenRequest struct {
+	Port           int      `required:"true" description:"endpoint port"`
+	ProtoFiles     []string `description:".proto files defining served services"`
+	ImportPaths    []string `description:".proto import paths, proto file directory is used if empty"`
+	DescriptorSets []string `description:"descriptor set files (protoc --descriptor_set_out --include_imports)"`
+	Rules          []*Rule  `description:"method rules, first matching rule applies, unmatched calls fail with UNIMPLEMENTED"`
+	Reflection     bool     `description:"flag to register server reflection service"`
+}
+
+// Init initialises request
+func (r *ListenRequest) Init() error {
+	for _, rule := range r.Rules {
+		if err := rule.Init(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks if request is valid.
+func (r ListenRequest) Validate() error {
+	if r.Port == 0 {
+		return errors.New("port was empty")
+	}
+	if len(r.ProtoFiles) == 0 && len(r.DescriptorSets) == 0 {
+		return errors.New("protoFiles and descriptorSets were empty")
+	}
+	for _, rule := range r.Rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListenResponse represents gRPC endpoint listen response
+type ListenResponse struct {
+	Port     int
+	Services []string
+}
+
+// ShutdownRequest represents gRPC endpoint shutdown request
+type ShutdownRequest struct {
+	Port int `required:"true" description:"endpoint port"`
+}
+
+// Validate checks if request is valid.
+func (r ShutdownRequest) Validate() error {
+	if r.Port == 0 {
+		return errors.New("port was empty")
+	}
+	return nil
+}
+
+// ShutdownResponse represents gRPC endpoint shutdown response
+type ShutdownResponse struct {
+	Calls int
+}
+
+// CallsRequest represents received calls journal request
+type CallsRequest struct {
+	Port   int    `required:"true" description:"endpoint port"`
+	Method string `description:"optional method suffix filter, i.e. Service/Method"`
+}
+
+// Validate checks if request is valid.
+func (r CallsRequest) Validate() error {
+	if r.Port == 0 {
+		return errors.New("port was empty")
+	}
+	return nil
+}
+
+// CallsResponse represents received calls journal response
+type CallsResponse struct {
+	Count int
+	Calls []*Call
+}
+
+// AssertRequest represents received calls journal assert request
+type AssertRequest struct {
+	Port        int         `required:"true" description:"endpoint port"`
+	Method      string      `description:"optional method suffix filter, i.e. Service/Method"`
+	Description string      `description:"validation description"`
+	Expect      interface{} `required:"true" description:"expected calls in received order (use @indexBy@ directive for any order), or map with Count and Calls keys"`
+}
+
+// Init initialises request
+func (r *AssertRequest) Init() error {
+	if r.Description == "" {
+		r.Description = fmt.Sprintf("gRPC endpoint :%v calls", r.Port)
+	}
+	return nil
+}
+
+// Validate checks if request is valid.
+func (r AssertRequest) Validate() error {
+	if r.Port == 0 {
+		return errors.New("port was empty")
+	}
+	if r.Expect == nil {
+		return errors.New("expect was empty")
+	}
+	return nil
+}
+
+// AssertResponse represents received calls journal assert response
+type AssertResponse struct {
+	*validator.AssertResponse
+	Count int
+}
+
+// ClearRequest represents received calls journal clear request
+type ClearRequest struct {
+	Port int `required:"true" description:"endpoint port"`
+}
+
+// Validate checks if request is valid.
+func (r ClearRequest) Validate() error {
+	if r.Port == 0 {
+		return errors.New("port was empty")
+	}
+	return nil
+}
+
+// ClearResponse represents received calls journal clear response
+type ClearResponse struct {
+	Cleared int
+}
@@ -0,0 +1,11 @@
+package grpc
+
+import (
+	"github.com/viant/endly"
+)
+
+func init() {
+	endly.Registry.Register(func() endly.Service {
+		return New()
+	})
+}
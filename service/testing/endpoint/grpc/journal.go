@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Call represents RPC received by gRPC endpoint
+type Call struct {
+	Index   int
+	Time    time.Time
+	Method  string
+	Header  map[string]interface{} `json:",omitempty"`
+	Request interface{}            `json:",omitempty" description:"request message, list of messages for client streaming method"`
+	Code    string
+}
+
+// AsMap returns recorded call as map used by assertion
+func (c *Call) AsMap() map[string]interface{} {
+	return map[string]interface{}{
+		"Index":   c.Index,
+		"Time":    c.Time,
+		"Method":  c.Method,
+		"Header":  c.Header,
+		"Request": c.Request,
+		"Code":    c.Code,
+	}
+}
+
+// Journal represents received calls journal
+type Journal struct {
+	mux   sync.RWMutex
+	calls []*Call
+}
+
+// Record records supplied call
+func (j *Journal) Record(call *Call) {
+	j.mux.Lock()
+	defer j.mux.Unlock()
+	call.Index = len(j.calls)
+	j.calls = append(j.calls, call)
+}
+
+// SetCode sets response status code for recorded call
+func (j *Journal) SetCode(call *Call, code string) {
+	j.mux.Lock()
+	defer j.mux.Unlock()
+	call.Code = code
+}
+
+// Calls returns recorded calls matching supplied method suffix, i.e. Service/Method, empty filter matches all
+func (j *Journal) Calls(method string) []*Call {
+	j.mux.RLock()
+	defer j.mux.RUnlock()
+	var result = make([]*Call, 0)
+	for _, call := range j.calls {
+		if method != "" && !strings.HasSuffix(call.Method, method) {
+			continue
+		}
+		recorded := *call
+		result = append(result, &recorded)
+	}
+	return result
+}
+
+// Clear removes all recorded calls, it returns number of removed calls
+func (j *Journal) Clear() int {
+	j.mux.Lock()
+	defer j.mux.Unlock()
+	result := len(j.calls)
+	j.calls = nil
+	return result
+}
+
+// NewJournal creates a new journal
+func NewJournal() *Journal {
+	return &Journal{calls: make([]*Call, 0)}
+}
@@ -0,0 +1,108 @@
+package grpc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/viant/assertly"
+	"github.com/viant/endly/internal/protoset"
+	"github.com/viant/endly/model/criteria"
+	"github.com/viant/endly/model/criteria/eval"
+	"github.com/viant/toolbox/data"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const (
+	requestStateKey  = "request"
+	requestsStateKey = "requests"
+	headerStateKey   = "header"
+)
+
+// Rule represents gRPC method mock rule, response fields are templates expanded with $request, $requests and $header
+type Rule struct {
+	Method    string                 `required:"true" description:"gRPC method, i.e. pkg.Service/Method"`
+	Request   interface{}            `description:"request matcher (assertly expected value), for client streaming method it is matched against received messages list"`
+	Header    map[string]interface{} `description:"request metadata matchers, key to expected value (assertly expression i.e. ~/regexp/)"`
+	When      string                 `description:"optional criteria evaluated against $request, $requests and $header"`
+	DelayMs   int                    `description:"delay before responding in ms"`
+	Code      string                 `description:"status code name (i.e. NOT_FOUND) or number, OK by default"`
+	Message   string                 `description:"status message template used with non OK code"`
+	Metadata  map[string]string      `description:"response header metadata templates"`
+	Trailer   map[string]string      `description:"response trailer metadata templates"`
+	Response  interface{}            `description:"JSON response template"`
+	Responses []interface{}          `description:"server streaming JSON response templates, each is sent as separate message"`
+	method    protoreflect.MethodDescriptor
+	code      codes.Code
+	whenEval  eval.Compute
+}
+
+// Init initialises rule
+func (r *Rule) Init() error {
+	if r.Code == "" {
+		r.code = codes.OK
+		return nil
+	}
+	if number, err := strconv.Atoi(r.Code); err == nil {
+		r.code = codes.Code(number)
+		return nil
+	}
+	return r.code.UnmarshalJSON([]byte(strconv.Quote(strings.ToUpper(r.Code))))
+}
+
+// Validate checks if rule is valid
+func (r *Rule) Validate() error {
+	if r.Method == "" {
+		return fmt.Errorf("rule method was empty")
+	}
+	return nil
+}
+
+// bind binds rule to method descriptor
+func (r *Rule) bind(files *protoregistry.Files) (err error) {
+	r.method, err = protoset.FindMethod(files, r.Method)
+	return err
+}
+
+func (r *Rule) matches(method protoreflect.MethodDescriptor, state data.Map) (bool, error) {
+	if r.method.FullName() != method.FullName() {
+		return false, nil
+	}
+	if len(r.Header) > 0 {
+		if ok, err := isMatched(r.Header, state.Get(headerStateKey), headerStateKey); !ok || err != nil {
+			return false, err
+		}
+	}
+	if r.Request != nil {
+		actual := state.Get(requestStateKey)
+		if method.IsStreamingClient() {
+			actual = state.Get(requestsStateKey)
+		}
+		if ok, err := isMatched(r.Request, actual, requestStateKey); !ok || err != nil {
+			return false, err
+		}
+	}
+	return criteria.Evaluate(nil, state, r.When, &r.whenEval, "Rule.When", true)
+}
+
+// responses returns expanded response messages
+func (r *Rule) responses(state data.Map) []interface{} {
+	if len(r.Responses) > 0 && r.method.IsStreamingServer() {
+		var result = make([]interface{}, 0, len(r.Responses))
+		for _, response := range r.Responses {
+			result = append(result, state.Expand(response))
+		}
+		return result
+	}
+	return []interface{}{state.Expand(r.Response)}
+}
+
+func isMatched(expected, actual interface{}, root string) (bool, error) {
+	validation, err := assertly.Assert(expected, actual, assertly.NewDataPath(root))
+	if err != nil {
+		return false, err
+	}
+	return !validation.HasFailure(), nil
+}
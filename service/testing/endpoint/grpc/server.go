@@ -0,0 +1,192 @@
+package grpc
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/viant/endly/internal/protoset"
+	"github.com/viant/toolbox/data"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Server represents gRPC mock server
+type Server struct {
+	*grpc.Server
+	files   *protoregistry.Files
+	rules   []*Rule
+	journal *Journal
+	base    data.Map
+}
+
+// GetServiceInfo returns services defined by loaded descriptors, it is used by reflection service
+func (s *Server) GetServiceInfo() map[string]grpc.ServiceInfo {
+	var result = make(map[string]grpc.ServiceInfo)
+	for _, service := range protoset.Services(s.files) {
+		info := grpc.ServiceInfo{Metadata: service.ParentFile().Path()}
+		methods := service.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			method := methods.Get(i)
+			info.Methods = append(info.Methods, grpc.MethodInfo{
+				Name:           string(method.Name()),
+				IsClientStream: method.IsStreamingClient(),
+				IsServerStream: method.IsStreamingServer(),
+			})
+		}
+		result[string(service.FullName())] = info
+	}
+	return result
+}
+
+// handle handles any RPC with the first matching rule
+func (s *Server) handle(_ interface{}, stream grpc.ServerStream) error {
+	fullMethod, ok := grpc.MethodFromServerStream(stream)
+	if !ok {
+		return status.Error(codes.Internal, "failed to get method from stream")
+	}
+	method, err := protoset.FindMethod(s.files, fullMethod)
+	if err != nil {
+		return status.Error(codes.Unimplemented, err.Error())
+	}
+	var requests = make([]interface{}, 0)
+	for {
+		message := dynamicpb.NewMessage(method.Input())
+		if err := stream.RecvMsg(message); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+		request, err := protoset.AsMap(message)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		requests = append(requests, request)
+		if !method.IsStreamingClient() {
+			break
+		}
+	}
+	call := &Call{
+		Time:   time.Now(),
+		Method: fullMethod,
+		Header: make(map[string]interface{}),
+	}
+	if incoming, ok := metadata.FromIncomingContext(stream.Context()); ok {
+		for key, values := range incoming {
+			call.Header[key] = strings.Join(values, ",")
+		}
+	}
+	var state = data.NewMap()
+	for k, v := range s.base {
+		state[k] = v
+	}
+	state.Put(headerStateKey, call.Header)
+	state.Put(requestsStateKey, requests)
+	call.Request = requests
+	if !method.IsStreamingClient() && len(requests) > 0 {
+		call.Request = requests[0]
+		state.Put(requestStateKey, requests[0])
+	}
+	s.journal.Record(call)
+	err = s.respond(stream, method, state)
+	s.journal.SetCode(call, status.Code(err).String())
+	return err
+}
+
+func (s *Server) respond(stream grpc.ServerStream, method protoreflect.MethodDescriptor, state data.Map) error {
+	rule, err := s.match(method, state)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if rule == nil {
+		return status.Errorf(codes.Unimplemented, "no rule matched %v", method.FullName())
+	}
+	if rule.DelayMs > 0 {
+		time.Sleep(time.Duration(rule.DelayMs) * time.Millisecond)
+	}
+	if len(rule.Metadata) > 0 {
+		if err := stream.SetHeader(expandMetadata(rule.Metadata, state)); err != nil {
+			return err
+		}
+	}
+	if len(rule.Trailer) > 0 {
+		stream.SetTrailer(expandMetadata(rule.Trailer, state))
+	}
+	if rule.code != codes.OK {
+		return status.Error(rule.code, state.ExpandAsText(rule.Message))
+	}
+	for _, response := range rule.responses(state) {
+		message, err := protoset.NewMessage(method.Output(), response)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		if err = stream.SendMsg(proto.Message(message)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) match(method protoreflect.MethodDescriptor, state data.Map) (*Rule, error) {
+	for _, rule := range s.rules {
+		matched, err := rule.matches(method, state)
+		if err != nil {
+			return nil, fmt.Errorf("failed to match rule %v: %w", rule.Method, err)
+		}
+		if matched {
+			return rule, nil
+		}
+	}
+	return nil, nil
+}
+
+func expandMetadata(values map[string]string, state data.Map) metadata.MD {
+	var result = metadata.MD{}
+	for key, value := range values {
+		result.Append(key, state.ExpandAsText(value))
+	}
+	return result
+}
+
+// StartServer starts gRPC mock server serving rules bound to supplied descriptors
+func StartServer(port int, files *protoregistry.Files, rules []*Rule, enableReflection bool, base data.Map) (*Server, error) {
+	for _, rule := range rules {
+		if err := rule.bind(files); err != nil {
+			return nil, err
+		}
+	}
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%v", port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start grpc server :%v, %v", port, err)
+	}
+	var result = &Server{
+		files:   files,
+		rules:   rules,
+		journal: NewJournal(),
+		base:    base,
+	}
+	result.Server = grpc.NewServer(grpc.UnknownServiceHandler(result.handle))
+	if enableReflection {
+		options := reflection.ServerOptions{Services: result, DescriptorResolver: files}
+		reflectionv1.RegisterServerReflectionServer(result.Server, reflection.NewServerV1(options))
+		reflectionv1alpha.RegisterServerReflectionServer(result.Server, reflection.NewServer(options))
+	}
+	go func() {
+		if err := result.Serve(listener); err != nil {
+			fmt.Printf("grpc server :%v stopped, %v\n", port, err)
+		}
+	}()
+	return result, nil
+}
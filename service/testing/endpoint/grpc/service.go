@@ -0,0 +1,221 @@
+package grpc
+
+import (
+	"fmt"
+
+	"github.com/viant/endly"
+	"github.com/viant/endly/internal/protoset"
+	"github.com/viant/endly/model/location"
+	"github.com/viant/endly/service/testing/validator"
+	"github.com/viant/toolbox"
+)
+
+const (
+	//ServiceID represents gRPC endpoint service id.
+	ServiceID = "grpc/endpoint"
+)
+
+// service represents gRPC endpoint service, that serves mocked RPCs
+type service struct {
+	*endly.AbstractService
+	servers map[int]*Server
+}
+
+func (s *service) listen(context *endly.Context, request *ListenRequest) (*ListenResponse, error) {
+	state := context.State()
+	var protoFiles = expandLocations(state.ExpandAsText, request.ProtoFiles)
+	var importPaths = expandLocations(state.ExpandAsText, request.ImportPaths)
+	var descriptorSets = expandLocations(state.ExpandAsText, request.DescriptorSets)
+	files, err := protoset.Load(protoFiles, importPaths, descriptorSets)
+	if err != nil {
+		return nil, err
+	}
+	s.Mutex().Lock()
+	defer s.Mutex().Unlock()
+	if _, ok := s.servers[request.Port]; ok {
+		return nil, fmt.Errorf("grpc endpoint already started on port: %v", request.Port)
+	}
+	server, err := StartServer(request.Port, files, request.Rules, request.Reflection, state.Clone())
+	if err != nil {
+		return nil, err
+	}
+	s.servers[request.Port] = server
+	response := &ListenResponse{Port: request.Port}
+	for _, service := range protoset.Services(files) {
+		response.Services = append(response.Services, string(service.FullName()))
+	}
+	return response, nil
+}
+
+func expandLocations(expand func(text string) string, locations []string) []string {
+	var result = make([]string, 0, len(locations))
+	for _, loc := range locations {
+		result = append(result, location.NewResource(expand(loc)).Path())
+	}
+	return result
+}
+
+func (s *service) server(port int) (*Server, error) {
+	s.Mutex().RLock()
+	defer s.Mutex().RUnlock()
+	server, ok := s.servers[port]
+	if !ok {
+		return nil, fmt.Errorf("grpc server not started on port: %v", port)
+	}
+	return server, nil
+}
+
+func (s *service) shutdown(context *endly.Context, request *ShutdownRequest) (*ShutdownResponse, error) {
+	s.Mutex().Lock()
+	server, ok := s.servers[request.Port]
+	delete(s.servers, request.Port)
+	s.Mutex().Unlock()
+	if !ok {
+		return nil, fmt.Errorf("grpc server not started on port: %v", request.Port)
+	}
+	server.GracefulStop()
+	return &ShutdownResponse{Calls: len(server.journal.Calls(""))}, nil
+}
+
+func (s *service) calls(context *endly.Context, request *CallsRequest) (*CallsResponse, error) {
+	server, err := s.server(request.Port)
+	if err != nil {
+		return nil, err
+	}
+	calls := server.journal.Calls(request.Method)
+	return &CallsResponse{Count: len(calls), Calls: calls}, nil
+}
+
+func (s *service) assert(context *endly.Context, request *AssertRequest) (*AssertResponse, error) {
+	server, err := s.server(request.Port)
+	if err != nil {
+		return nil, err
+	}
+	calls := server.journal.Calls(request.Method)
+	var recorded = make([]interface{}, 0, len(calls))
+	for _, call := range calls {
+		recorded = append(recorded, call.AsMap())
+	}
+	var actual interface{} = recorded
+	if toolbox.IsMap(request.Expect) {
+		actual = map[string]interface{}{
+			"Count": len(calls),
+			"Calls": recorded,
+		}
+	}
+	response := &AssertResponse{Count: len(calls)}
+	response.AssertResponse, err = validator.Assert(context, request, request.Expect, actual, fmt.Sprintf("%v.calls", ServiceID), request.Description)
+	return response, err
+}
+
+func (s *service) clear(context *endly.Context, request *ClearRequest) (*ClearResponse, error) {
+	server, err := s.server(request.Port)
+	if err != nil {
+		return nil, err
+	}
+	return &ClearResponse{Cleared: server.journal.Clear()}, nil
+}
+
+func (s *service) registerRoutes() {
+	s.Register(&endly.Route{
+		Action: "listen",
+		RequestInfo: &endly.ActionInfo{
+			Description: "start gRPC endpoint serving mocked methods",
+		},
+		RequestProvider: func() interface{} {
+			return &ListenRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &ListenResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*ListenRequest); ok {
+				return s.listen(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+	s.Register(&endly.Route{
+		Action: "shutdown",
+		RequestInfo: &endly.ActionInfo{
+			Description: "stop gRPC endpoint",
+		},
+		RequestProvider: func() interface{} {
+			return &ShutdownRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &ShutdownResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*ShutdownRequest); ok {
+				return s.shutdown(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+	s.Register(&endly.Route{
+		Action: "calls",
+		RequestInfo: &endly.ActionInfo{
+			Description: "return calls received by gRPC endpoint",
+		},
+		RequestProvider: func() interface{} {
+			return &CallsRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &CallsResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*CallsRequest); ok {
+				return s.calls(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+	s.Register(&endly.Route{
+		Action: "assert",
+		RequestInfo: &endly.ActionInfo{
+			Description: "assert calls received by gRPC endpoint",
+		},
+		RequestProvider: func() interface{} {
+			return &AssertRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &AssertResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*AssertRequest); ok {
+				return s.assert(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+	s.Register(&endly.Route{
+		Action: "clear",
+		RequestInfo: &endly.ActionInfo{
+			Description: "clear calls received by gRPC endpoint",
+		},
+		RequestProvider: func() interface{} {
+			return &ClearRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &ClearResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*ClearRequest); ok {
+				return s.clear(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+}
+
+// New creates a new gRPC endpoint service, to serve mocked RPCs.
+func New() endly.Service {
+	var result = &service{
+		servers:         make(map[int]*Server),
+		AbstractService: endly.NewAbstractService(ServiceID),
+	}
+	result.AbstractService.Service = result
+	result.registerRoutes()
+	return result
+}
@@ -0,0 +1,125 @@
+package grpc_test
+
+import (
+	"context"
+	"io"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/internal/protoset"
+	endpoint "github.com/viant/endly/service/testing/endpoint/grpc"
+	"github.com/viant/toolbox"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestGRPCEndpointService_Listen(t *testing.T) {
+	manager := endly.New()
+	ctx := manager.NewContext(toolbox.NewContext())
+	service, _ := ctx.Service(endpoint.ServiceID)
+	protoFile := path.Join(toolbox.CallerDirectory(3), "test", "greeter.proto")
+
+	response := service.Run(ctx, &endpoint.ListenRequest{
+		Port:       7730,
+		ProtoFiles: []string{protoFile},
+		Reflection: true,
+		Rules: []*endpoint.Rule{
+			{Method: "greeter.Greeter/SayHello", Request: map[string]interface{}{"name": "error"}, Code: "NOT_FOUND", Message: "no ${request.name}"},
+			{Method: "greeter.Greeter/SayHello", Response: map[string]interface{}{"message": "Hello ${request.name}"}, Metadata: map[string]string{"x-mock": "true"}},
+			{Method: "greeter.Greeter/StreamHello", Responses: []interface{}{`{"message":"1 ${request.name}"}`, `{"message":"2 ${request.name}"}`}},
+			{Method: "greeter.Greeter/CollectHello", Response: map[string]interface{}{"message": "${requests[0].name} and ${requests[1].name}"}},
+		},
+	})
+	if !assert.Equal(t, "", response.Error) {
+		return
+	}
+	assert.EqualValues(t, []string{"greeter.Greeter"}, response.Response.(*endpoint.ListenResponse).Services)
+
+	files, err := protoset.Load([]string{protoFile}, nil, nil)
+	if !assert.Nil(t, err) {
+		return
+	}
+	conn, err := grpc.NewClient("127.0.0.1:7730", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer conn.Close()
+	callContext, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	callContext = metadata.AppendToOutgoingContext(callContext, "x-client", "test")
+
+	sayHello, _ := protoset.FindMethod(files, "greeter.Greeter/SayHello")
+	request, _ := protoset.NewMessage(sayHello.Input(), map[string]interface{}{"name": "Bob"})
+	reply := dynamicpb.NewMessage(sayHello.Output())
+	var header metadata.MD
+	if assert.Nil(t, conn.Invoke(callContext, "/greeter.Greeter/SayHello", request, reply, grpc.Header(&header))) {
+		replyMap, _ := protoset.AsMap(reply)
+		assert.Equal(t, "Hello Bob", replyMap["message"])
+		assert.EqualValues(t, []string{"true"}, header.Get("x-mock"))
+	}
+	request, _ = protoset.NewMessage(sayHello.Input(), `{"name":"error"}`)
+	err = conn.Invoke(callContext, "/greeter.Greeter/SayHello", request, dynamicpb.NewMessage(sayHello.Output()))
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "no error", status.Convert(err).Message())
+
+	streamHello, _ := protoset.FindMethod(files, "greeter.Greeter/StreamHello")
+	stream, err := conn.NewStream(callContext, &grpc.StreamDesc{ServerStreams: true}, "/greeter.Greeter/StreamHello")
+	if assert.Nil(t, err) {
+		request, _ = protoset.NewMessage(streamHello.Input(), map[string]interface{}{"name": "Ann"})
+		assert.Nil(t, stream.SendMsg(request))
+		assert.Nil(t, stream.CloseSend())
+		var messages = make([]interface{}, 0)
+		for {
+			reply := dynamicpb.NewMessage(streamHello.Output())
+			if err := stream.RecvMsg(reply); err != nil {
+				assert.Equal(t, io.EOF, err)
+				break
+			}
+			replyMap, _ := protoset.AsMap(reply)
+			messages = append(messages, replyMap["message"])
+		}
+		assert.EqualValues(t, []interface{}{"1 Ann", "2 Ann"}, messages)
+	}
+
+	collectHello, _ := protoset.FindMethod(files, "greeter.Greeter/CollectHello")
+	stream, err = conn.NewStream(callContext, &grpc.StreamDesc{ClientStreams: true}, "/greeter.Greeter/CollectHello")
+	if assert.Nil(t, err) {
+		for _, name := range []string{"Ann", "Bob"} {
+			request, _ = protoset.NewMessage(collectHello.Input(), map[string]interface{}{"name": name})
+			assert.Nil(t, stream.SendMsg(request))
+		}
+		assert.Nil(t, stream.CloseSend())
+		reply := dynamicpb.NewMessage(collectHello.Output())
+		if assert.Nil(t, stream.RecvMsg(reply)) {
+			replyMap, _ := protoset.AsMap(reply)
+			assert.Equal(t, "Ann and Bob", replyMap["message"])
+		}
+	}
+
+	response = service.Run(ctx, &endpoint.AssertRequest{
+		Port:   7730,
+		Method: "Greeter/SayHello",
+		Expect: []interface{}{
+			map[string]interface{}{"Request": map[string]interface{}{"name": "Bob"}, "Header": map[string]interface{}{"x-client": "test"}, "Code": "OK"},
+			map[string]interface{}{"Request": map[string]interface{}{"name": "error"}, "Code": "NotFound"},
+		},
+	})
+	if assert.Equal(t, "", response.Error) {
+		assertResponse := response.Response.(*endpoint.AssertResponse)
+		assert.Equal(t, 2, assertResponse.Count)
+		assert.Equal(t, 0, assertResponse.FailedCount, assertResponse.Report())
+	}
+	response = service.Run(ctx, &endpoint.ClearRequest{Port: 7730})
+	if assert.Equal(t, "", response.Error) {
+		assert.Equal(t, 4, response.Response.(*endpoint.ClearResponse).Cleared)
+	}
+	response = service.Run(ctx, &endpoint.ShutdownRequest{Port: 7730})
+	assert.Equal(t, "", response.Error)
+}
@@ -16,4 +16,6 @@ const (
 	DefaultRequestTemplate = "%02d-req.json"
 	//DefaultResponseTemplate response tempalte
 	DefaultResponseTemplate = "%02d-resp.json"
+	//DefaultJournalSize max number of journaled requests
+	DefaultJournalSize = 1000
 )
@@ -2,22 +2,32 @@ package http
 
 import (
 	"errors"
+	"fmt"
 	"sync"
+
+	"github.com/viant/endly/service/testing/validator"
 )
 
 // ListenRequest represent HTTP endpoint listen request
 type ListenRequest struct {
 	Port             int
 	Rotate           bool
-	RequestTemplate  string   `description:"request file loading template, default: %02d-req.json"`
-	ResponseTemplate string   `description:"response file loading template, default: %02d-resp.json"`
-	BaseDirectory    string   `required:"true" description:"location with replay files (could be generate by https://github.com/viant/toolbox/blob/master/bridge/http_bridge_recording_util.go#L81"`
-	IndexKeys        []string `description:"recorded requests matching keys, by default: Method,URL,Body,Cookie,Content-Type"`
+	RequestTemplate  string                 `description:"request file loading template, default: %02d-req.json"`
+	ResponseTemplate string                 `description:"response file loading template, default: %02d-resp.json"`
+	BaseDirectory    string                 `required:"true" description:"location with replay files (could be generate by https://github.com/viant/toolbox/blob/master/bridge/http_bridge_recording_util.go#L81"`
+	IndexKeys        []string               `description:"recorded requests matching keys, by default: Method,URL,Body,Cookie,Content-Type"`
+	Rules            []*Rule                `description:"dynamic route rules matched before recorded trips, responses are templates expanded with $path, $query, $header, $body and $state"`
+	State            map[string]interface{} `description:"initial endpoint state, rules read it with $state and update it with set/delete"`
+	Faults           []*Fault               `description:"faults injected into matching requests, first matching fault applies"`
+	TLS              *TLS                   `description:"TLS settings, endpoint serves HTTPS if specified"`
+	HTTP2            bool                   `description:"flag to enable HTTP/2, without TLS endpoint serves HTTP/2 cleartext (h2c) with prior knowledge"`
+	JournalSize      int                    `description:"max number of received requests kept in the journal, the oldest are dropped, default 1000"`
 }
 
 // ListenResponse represents HTTP endpoint listen response with indexed trips
 type ListenResponse struct {
 	Trips map[string]*HTTPResponses
+	CA    string `json:",omitempty" description:"generated CA certificate PEM"`
 }
 
 func (r *ListenRequest) Init() error {
@@ -27,6 +37,22 @@ func (r *ListenRequest) Init() error {
 	if r.ResponseTemplate == "" {
 		r.ResponseTemplate = DefaultResponseTemplate
 	}
+	if r.JournalSize == 0 {
+		r.JournalSize = DefaultJournalSize
+	}
+	for _, rule := range r.Rules {
+		if err := rule.Init(); err != nil {
+			return err
+		}
+	}
+	for _, fault := range r.Faults {
+		if err := fault.Init(); err != nil {
+			return err
+		}
+	}
+	if r.TLS != nil {
+		return r.TLS.Init()
+	}
 	return nil
 }
 
@@ -35,6 +61,19 @@ func (r ListenRequest) Validate() error {
 	if r.Port == 0 {
 		return errors.New("port was empty")
 	}
+	for _, rule := range r.Rules {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	for _, fault := range r.Faults {
+		if err := fault.Validate(); err != nil {
+			return err
+		}
+	}
+	if r.TLS != nil {
+		return r.TLS.Validate()
+	}
 	return nil
 }
 
@@ -49,6 +88,9 @@ func (r ListenRequest) AsHTTPServerTrips() *HTTPServerTrips {
 		Trips:         make(map[string]*HTTPResponses),
 		IndexKeys:     r.IndexKeys,
 		Mutex:         &sync.Mutex{},
+		TLS:           r.TLS,
+		HTTP2:         r.HTTP2,
+		JournalSize:   r.JournalSize,
 	}
 }
 
@@ -89,3 +131,172 @@ func (r AppendRequest) AsHTTPServerTrips(rotate bool, indexKeys []string) *HTTPS
 		Mutex:         &sync.Mutex{},
 	}
 }
+
+// RequestsRequest represents received requests journal request
+type RequestsRequest struct {
+	Port   int    `required:"true" description:"endpoint port"`
+	Method string `description:"optional HTTP method filter"`
+	Path   string `description:"optional path pattern filter, i.e. /users/{id}"`
+}
+
+// Validate checks if request is valid.
+func (r RequestsRequest) Validate() error {
+	if r.Port == 0 {
+		return errors.New("port was empty")
+	}
+	return nil
+}
+
+// RequestsResponse represents received requests journal response
+type RequestsResponse struct {
+	Count    int
+	Requests []*RecordedRequest
+}
+
+// AssertRequest represents received requests journal assert request
+type AssertRequest struct {
+	Port        int         `required:"true" description:"endpoint port"`
+	Method      string      `description:"optional HTTP method filter"`
+	Path        string      `description:"optional path pattern filter, i.e. /users/{id}"`
+	Description string      `description:"validation description"`
+	Expect      interface{} `required:"true" description:"expected requests in received order (use @indexBy@ directive for any order), or map with Count and Requests keys"`
+}
+
+// Init initialises request
+func (r *AssertRequest) Init() error {
+	if r.Description == "" {
+		r.Description = fmt.Sprintf("HTTP endpoint :%v requests", r.Port)
+	}
+	return nil
+}
+
+// Validate checks if request is valid.
+func (r AssertRequest) Validate() error {
+	if r.Port == 0 {
+		return errors.New("port was empty")
+	}
+	if r.Expect == nil {
+		return errors.New("expect was empty")
+	}
+	return nil
+}
+
+// AssertResponse represents received requests journal assert response
+type AssertResponse struct {
+	*validator.AssertResponse
+	Count int
+}
+
+// ClearRequest represents received requests journal clear request
+type ClearRequest struct {
+	Port int `required:"true" description:"endpoint port"`
+}
+
+// Validate checks if request is valid.
+func (r ClearRequest) Validate() error {
+	if r.Port == 0 {
+		return errors.New("port was empty")
+	}
+	return nil
+}
+
+// ClearResponse represents received requests journal clear response
+type ClearResponse struct {
+	Cleared int
+}
+
+// FaultRequest represents runtime fault injection update request
+type FaultRequest struct {
+	Port   int      `required:"true" description:"endpoint port"`
+	Faults []*Fault `description:"faults replacing endpoint faults, empty faults disable fault injection"`
+}
+
+// Init initialises request
+func (r *FaultRequest) Init() error {
+	for _, fault := range r.Faults {
+		if err := fault.Init(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Validate checks if request is valid.
+func (r FaultRequest) Validate() error {
+	if r.Port == 0 {
+		return errors.New("port was empty")
+	}
+	for _, fault := range r.Faults {
+		if err := fault.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FaultResponse represents runtime fault injection update response
+type FaultResponse struct {
+	Faults int
+}
+
+// RecordRequest represents recording reverse proxy start request
+type RecordRequest struct {
+	Port             int      `required:"true" description:"recording proxy port"`
+	Targets          []string `required:"true" description:"target URLs, target URL path is used as proxy route pattern"`
+	Directory        string   `description:"capture location, default: http_recording-<uuid> in current directory"`
+	Redact           []string `description:"redacted request/response headers, default: Authorization, Proxy-Authorization, Set-Cookie"`
+	RequestTemplate  string   `description:"request file template, default: %02d-req.json"`
+	ResponseTemplate string   `description:"response file template, default: %02d-resp.json"`
+}
+
+// Init initialises request
+func (r *RecordRequest) Init() error {
+	if r.RequestTemplate == "" {
+		r.RequestTemplate = DefaultRequestTemplate
+	}
+	if r.ResponseTemplate == "" {
+		r.ResponseTemplate = DefaultResponseTemplate
+	}
+	if len(r.Redact) == 0 {
+		r.Redact = DefaultRedactedHeaders
+	}
+	return nil
+}
+
+// Validate checks if request is valid.
+func (r RecordRequest) Validate() error {
+	if r.Port == 0 {
+		return errors.New("port was empty")
+	}
+	if len(r.Targets) == 0 {
+		return errors.New("targets were empty")
+	}
+	return nil
+}
+
+// RecordResponse represents recording reverse proxy start response
+type RecordResponse struct {
+	Port      int
+	Directory string
+}
+
+// StopRequest represents recording reverse proxy stop request
+type StopRequest struct {
+	Port int `required:"true" description:"recording proxy port"`
+}
+
+// Validate checks if request is valid.
+func (r StopRequest) Validate() error {
+	if r.Port == 0 {
+		return errors.New("port was empty")
+	}
+	return nil
+}
+
+// StopResponse represents recording reverse proxy stop response, directory can be used as ListenRequest baseDirectory
+type StopResponse struct {
+	Directory        string
+	Trips            int
+	RequestTemplate  string
+	ResponseTemplate string
+}
@@ -0,0 +1,267 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	//LatencyFixed represents fixed latency distribution
+	LatencyFixed = "fixed"
+	//LatencyUniform represents uniform latency distribution between Ms and MaxMs
+	LatencyUniform = "uniform"
+	//LatencyNormal represents normal latency distribution with Ms mean and StdDevMs standard deviation
+	LatencyNormal = "normal"
+)
+
+// Latency represents injected latency
+type Latency struct {
+	Distribution string `description:"fixed (default), uniform or normal"`
+	Ms           int    `description:"fixed latency, uniform min or normal mean in ms"`
+	MaxMs        int    `description:"uniform distribution max latency in ms"`
+	StdDevMs     int    `description:"normal distribution standard deviation in ms"`
+}
+
+// Duration returns next latency duration
+func (l *Latency) Duration() time.Duration {
+	var ms = float64(l.Ms)
+	switch l.Distribution {
+	case LatencyUniform:
+		if l.MaxMs > l.Ms {
+			ms += rand.Float64() * float64(l.MaxMs-l.Ms)
+		}
+	case LatencyNormal:
+		ms += rand.NormFloat64() * float64(l.StdDevMs)
+	}
+	if ms < 0 {
+		ms = 0
+	}
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// Validate checks if latency is valid
+func (l *Latency) Validate() error {
+	switch l.Distribution {
+	case "", LatencyFixed, LatencyUniform, LatencyNormal:
+		return nil
+	}
+	return fmt.Errorf("unsupported latency distribution: %v", l.Distribution)
+}
+
+// Fault represents fault injected into matching requests handling
+type Fault struct {
+	Method     string   `description:"HTTP method filter, any method matches if empty"`
+	Path       string   `description:"path pattern filter (see rule path), any path matches if empty"`
+	Latency    *Latency `description:"latency injected before handling request"`
+	Reset      bool     `description:"flag to reset connection without response"`
+	Truncate   int      `description:"if set, response body is truncated to specified number of bytes and connection is closed"`
+	StatusCode int      `description:"if set, responds with this status code instead of configured rule or trip response"`
+	Body       string   `description:"response body used with statusCode"`
+	Calls      []int    `description:"1-based numbers of matching calls the fault applies to, all calls if empty"`
+	Percentage float64  `description:"percentage (0-100) of matching calls the fault applies to, all calls if zero"`
+	filter     *Rule
+	calls      int
+}
+
+// Init initialises fault
+func (f *Fault) Init() error {
+	f.filter = &Rule{Method: f.Method, Path: f.Path}
+	return f.filter.Init()
+}
+
+// Validate checks if fault is valid
+func (f *Fault) Validate() error {
+	if f.Percentage < 0 || f.Percentage > 100 {
+		return fmt.Errorf("invalid fault percentage: %v", f.Percentage)
+	}
+	if f.Latency != nil {
+		return f.Latency.Validate()
+	}
+	return nil
+}
+
+// applies returns true if fault has to be applied to supplied request, it counts matching calls
+func (f *Fault) applies(request *http.Request) bool {
+	if f.filter.Method != "" && f.filter.Method != request.Method {
+		return false
+	}
+	if f.Path != "" {
+		if _, ok := f.filter.matchPath(request.URL.Path); !ok {
+			return false
+		}
+	}
+	f.calls++
+	if len(f.Calls) > 0 {
+		selected := false
+		for _, call := range f.Calls {
+			if call == f.calls {
+				selected = true
+				break
+			}
+		}
+		if !selected {
+			return false
+		}
+	}
+	if f.Percentage > 0 && rand.Float64()*100 >= f.Percentage {
+		return false
+	}
+	return true
+}
+
+// Faults represents endpoint faults, they can be replaced at runtime
+type Faults struct {
+	mux    sync.Mutex
+	faults []*Fault
+}
+
+// Set replaces faults
+func (f *Faults) Set(faults []*Fault) {
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	f.faults = faults
+}
+
+// List returns faults copy
+func (f *Faults) List() []*Fault {
+	if f == nil {
+		return nil
+	}
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return append([]*Fault{}, f.faults...)
+}
+
+// Len returns number of faults
+func (f *Faults) Len() int {
+	if f == nil {
+		return 0
+	}
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	return len(f.faults)
+}
+
+// Match returns the first fault applying to supplied request
+func (f *Faults) Match(request *http.Request) *Fault {
+	if f == nil {
+		return nil
+	}
+	f.mux.Lock()
+	defer f.mux.Unlock()
+	for _, fault := range f.faults {
+		if fault.applies(request) {
+			return fault
+		}
+	}
+	return nil
+}
+
+// Inject applies fault, it returns writer used by subsequent handler and flag if request was already handled
+func (f *Fault) Inject(writer http.ResponseWriter) (http.ResponseWriter, bool) {
+	if f.Latency != nil {
+		time.Sleep(f.Latency.Duration())
+	}
+	if f.Reset {
+		resetConnection(writer)
+		return writer, true
+	}
+	if f.StatusCode > 0 {
+		if f.Truncate > 0 {
+			writer = &truncatedWriter{ResponseWriter: writer, limit: f.Truncate, header: http.Header{}}
+		}
+		writer.WriteHeader(f.StatusCode)
+		if f.Body != "" {
+			_, _ = writer.Write([]byte(f.Body))
+		}
+		if truncated, ok := writer.(*truncatedWriter); ok {
+			truncated.Close()
+		}
+		return writer, true
+	}
+	if f.Truncate > 0 {
+		return &truncatedWriter{ResponseWriter: writer, limit: f.Truncate, header: http.Header{}}, false
+	}
+	return writer, false
+}
+
+// truncatedWriter represents response writer that buffers response and writes only first limit bytes of the body
+type truncatedWriter struct {
+	http.ResponseWriter
+	header http.Header
+	code   int
+	body   bytes.Buffer
+	limit  int
+}
+
+func (w *truncatedWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *truncatedWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+}
+
+func (w *truncatedWriter) Write(data []byte) (int, error) {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	return w.body.Write(data)
+}
+
+// Close writes declared full length response with truncated body and closes connection
+func (w *truncatedWriter) Close() {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	for k, v := range w.header {
+		w.ResponseWriter.Header()[k] = v
+	}
+	body := w.body.Bytes()
+	w.ResponseWriter.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.ResponseWriter.WriteHeader(w.code)
+	if w.limit < len(body) {
+		body = body[:w.limit]
+	}
+	_, _ = w.ResponseWriter.Write(body)
+	if hijacker, ok := w.ResponseWriter.(http.Hijacker); ok {
+		if conn, _, err := hijacker.Hijack(); err == nil {
+			_ = conn.Close()
+			return
+		}
+	}
+	//HTTP/2 stream can not be hijacked, aborting handler resets the stream
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	panic(http.ErrAbortHandler)
+}
+
+// resetConnection closes client connection with TCP RST, HTTP/2 stream is reset by aborting handler
+func resetConnection(writer http.ResponseWriter) {
+	hijacker, ok := writer.(http.Hijacker)
+	if !ok {
+		panic(http.ErrAbortHandler)
+	}
+	conn, _, err := hijacker.Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		_ = tcpConn.SetLinger(0)
+	}
+	_ = conn.Close()
+}
+
+// NewFaults creates endpoint faults
+func NewFaults(faults []*Fault) *Faults {
+	return &Faults{faults: faults}
+}
@@ -15,6 +15,7 @@ type httpHandler struct {
 	running   int32
 	handler   func(writer http.ResponseWriter, request *http.Request)
 	thinkTime time.Duration
+	journal   *Journal
 }
 
 const (
@@ -26,18 +27,44 @@ func (h *httpHandler) ServeHTTP(writer http.ResponseWriter, request *http.Reques
 		h.thinkTime = time.Duration(toolbox.AsInt(thinkTime)) * time.Millisecond
 		fmt.Printf("Updated think time: %s\n", h.thinkTime)
 	}
-	h.handler(writer, request)
+	recorded := h.journal.Record(request)
+	statusWriter := &statusWriter{ResponseWriter: writer}
+	h.handler(statusWriter, request)
+	h.journal.SetCode(recorded, statusWriter.code)
 }
 
 func getServerHandler(httpServer *http.Server, httpHandler *httpHandler, trips *HTTPServerTrips) func(writer http.ResponseWriter, request *http.Request) {
 	return func(writer http.ResponseWriter, request *http.Request) {
+		if fault := trips.Faults.Match(request); fault != nil {
+			var handled bool
+			if writer, handled = fault.Inject(writer); handled {
+				return
+			}
+			if truncated, ok := writer.(*truncatedWriter); ok {
+				defer truncated.Close()
+			}
+		}
 		trips.Mutex.Lock()
 		defer trips.Mutex.Unlock()
 		if atomic.LoadInt32(&httpHandler.running) == 0 {
 			return
 		}
+		rule, state, err := trips.Mock.Match(request)
+		if err != nil {
+			http.Error(writer, fmt.Sprintf("%v", err), http.StatusInternalServerError)
+			return
+		}
+		if rule != nil {
+			if httpHandler.thinkTime > 0 {
+				time.Sleep(httpHandler.thinkTime)
+			}
+			if err = trips.Mock.Handle(writer, rule, state); err != nil {
+				log.Print(err)
+			}
+			return
+		}
 
-		var key, err = buildKeyValue(trips.IndexKeys, request)
+		key, err := buildKeyValue(trips.IndexKeys, request)
 		if err != nil {
 			http.Error(writer, fmt.Sprintf("%v", err), http.StatusInternalServerError)
 			return
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/viant/toolbox"
+)
+
+// RecordedRequest represents request received by HTTP endpoint
+type RecordedRequest struct {
+	Index    int
+	Time     time.Time
+	Method   string
+	URL      string
+	Path     string
+	Query    map[string]interface{} `json:",omitempty"`
+	Header   map[string]interface{} `json:",omitempty"`
+	Body     string                 `json:",omitempty"`
+	JSONBody interface{}            `json:",omitempty"`
+	Code     int
+}
+
+// AsMap returns recorded request as map used by assertion
+func (r *RecordedRequest) AsMap() map[string]interface{} {
+	var result = map[string]interface{}{
+		"Index":  r.Index,
+		"Time":   r.Time,
+		"Method": r.Method,
+		"URL":    r.URL,
+		"Path":   r.Path,
+		"Query":  r.Query,
+		"Header": r.Header,
+		"Body":   r.Body,
+		"Code":   r.Code,
+	}
+	if r.JSONBody != nil {
+		result["JSONBody"] = r.JSONBody
+	}
+	return result
+}
+
+// Journal represents received requests journal, it keeps up to max last requests
+type Journal struct {
+	mux      sync.RWMutex
+	max      int
+	count    int
+	requests []*RecordedRequest
+}
+
+// Record records supplied request, request body is restored for subsequent readers
+func (j *Journal) Record(request *http.Request) *RecordedRequest {
+	var content []byte
+	if request.Body != nil {
+		content, _ = ioutil.ReadAll(request.Body)
+		request.Body = ioutil.NopCloser(bytes.NewReader(content))
+	}
+	var result = &RecordedRequest{
+		Time:   time.Now(),
+		Method: request.Method,
+		URL:    request.URL.String(),
+		Path:   request.URL.Path,
+		Query:  make(map[string]interface{}),
+		Header: make(map[string]interface{}),
+		Body:   string(content),
+	}
+	for key, values := range request.URL.Query() {
+		result.Query[key] = strings.Join(values, ",")
+	}
+	for key, values := range request.Header {
+		result.Header[key] = strings.Join(values, ",")
+	}
+	if len(content) > 0 && toolbox.IsStructuredJSON(result.Body) {
+		var decoded interface{}
+		if err := json.Unmarshal(content, &decoded); err == nil {
+			result.JSONBody = decoded
+		}
+	}
+	j.mux.Lock()
+	defer j.mux.Unlock()
+	result.Index = j.count
+	j.count++
+	if j.max > 0 && len(j.requests) >= j.max {
+		j.requests = append(j.requests[:0], j.requests[len(j.requests)-j.max+1:]...)
+	}
+	j.requests = append(j.requests, result)
+	return result
+}
+
+// SetCode sets response status code for recorded request
+func (j *Journal) SetCode(request *RecordedRequest, code int) {
+	j.mux.Lock()
+	defer j.mux.Unlock()
+	request.Code = code
+}
+
+// Requests returns recorded requests matching supplied method and path pattern (see Rule.Path), empty filter matches all
+func (j *Journal) Requests(method, pathPattern string) []*RecordedRequest {
+	var filter = &Rule{Method: method, Path: pathPattern}
+	_ = filter.Init()
+	j.mux.RLock()
+	defer j.mux.RUnlock()
+	var result = make([]*RecordedRequest, 0)
+	for _, request := range j.requests {
+		if filter.Method != "" && filter.Method != request.Method {
+			continue
+		}
+		if pathPattern != "" {
+			if _, ok := filter.matchPath(request.Path); !ok {
+				continue
+			}
+		}
+		recorded := *request
+		result = append(result, &recorded)
+	}
+	return result
+}
+
+// Clear removes all recorded requests, it returns number of removed requests
+func (j *Journal) Clear() int {
+	j.mux.Lock()
+	defer j.mux.Unlock()
+	result := len(j.requests)
+	j.requests = nil
+	j.count = 0
+	return result
+}
+
+// statusWriter represents response writer capturing status code
+type statusWriter struct {
+	http.ResponseWriter
+	code int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if w.code == 0 {
+		w.code = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(data []byte) (int, error) {
+	if w.code == 0 {
+		w.code = http.StatusOK
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *statusWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking: %T", w.ResponseWriter)
+	}
+	return hijacker.Hijack()
+}
+
+func (w *statusWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// NewJournal creates a new journal, max limits number of kept requests, 0 - no limit
+func NewJournal(max int) *Journal {
+	return &Journal{max: max, requests: make([]*RecordedRequest, 0)}
+}
@@ -0,0 +1,274 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/viant/assertly"
+	"github.com/viant/endly/model/criteria"
+	"github.com/viant/endly/model/criteria/eval"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+)
+
+const (
+	requestStateKey = "request"
+	pathStateKey    = "path"
+	queryStateKey   = "query"
+	headerStateKey  = "header"
+	bodyStateKey    = "body"
+	mockStateKey    = "state"
+	suffixParam     = "suffix"
+)
+
+// Rule represents dynamic mock route rule, response and state updates are templates expanded against the matched request
+type Rule struct {
+	Method   string                 `description:"HTTP method, any method matches if empty"`
+	Path     string                 `required:"true" description:"URI path pattern with {param} placeholders i.e. /users/{id}, trailing /* matches any suffix captured as $path.suffix"`
+	Header   map[string]interface{} `description:"request header matchers, header name to expected value (assertly expression i.e. ~/regexp/)"`
+	Body     interface{}            `description:"JSON request body matcher (assertly expected value), only listed fields are checked"`
+	Exists   []string               `description:"endpoint state keys that have to exist for rule to match, keys are templates, i.e. users.${path.id}"`
+	When     string                 `description:"optional criteria evaluated against $path, $query, $header, $body and $state"`
+	Set      map[string]interface{} `description:"endpoint state updates applied on match, keys and values are templates, i.e. users.${body.id}: $body"`
+	Delete   []string               `description:"endpoint state keys removed on match, i.e. users.${path.id}"`
+	Response *RuleResponse          `description:"response template"`
+	segments []string
+	whenEval eval.Compute
+}
+
+// RuleResponse represents rule response template
+type RuleResponse struct {
+	Code   int               `description:"status code, default 200"`
+	Header map[string]string `description:"response headers"`
+	Body   interface{}       `description:"response body template, non text body is JSON encoded"`
+}
+
+// Init initialises rule
+func (r *Rule) Init() error {
+	r.Method = strings.ToUpper(r.Method)
+	r.segments = strings.Split(strings.Trim(r.Path, "/"), "/")
+	if r.Response == nil {
+		r.Response = &RuleResponse{}
+	}
+	if r.Response.Code == 0 {
+		r.Response.Code = http.StatusOK
+	}
+	return nil
+}
+
+// Validate checks if rule is valid
+func (r *Rule) Validate() error {
+	if r.Path == "" {
+		return fmt.Errorf("rule path was empty")
+	}
+	return nil
+}
+
+// matchPath returns path parameters if supplied URI path matches rule path pattern
+func (r *Rule) matchPath(URIPath string) (map[string]interface{}, bool) {
+	segments := strings.Split(strings.Trim(URIPath, "/"), "/")
+	var params = make(map[string]interface{})
+	for i, pattern := range r.segments {
+		if pattern == "*" && i == len(r.segments)-1 {
+			params[suffixParam] = strings.Join(segments[i:], "/")
+			return params, true
+		}
+		if i >= len(segments) {
+			return nil, false
+		}
+		if strings.HasPrefix(pattern, "{") && strings.HasSuffix(pattern, "}") {
+			params[pattern[1:len(pattern)-1]] = segments[i]
+			continue
+		}
+		if pattern != segments[i] {
+			return nil, false
+		}
+	}
+	return params, len(segments) == len(r.segments)
+}
+
+func (r *Rule) matches(request *http.Request, state data.Map) (bool, error) {
+	if r.Method != "" && r.Method != request.Method {
+		return false, nil
+	}
+	params, ok := r.matchPath(request.URL.Path)
+	if !ok {
+		return false, nil
+	}
+	state.Put(pathStateKey, params)
+	if len(r.Header) > 0 {
+		if ok, err := isMatched(r.Header, state.Get(headerStateKey), headerStateKey); !ok || err != nil {
+			return false, err
+		}
+	}
+	if r.Body != nil {
+		if ok, err := isMatched(r.Body, state.Get(bodyStateKey), bodyStateKey); !ok || err != nil {
+			return false, err
+		}
+	}
+	if len(r.Exists) > 0 {
+		mockState := data.Map(toolbox.AsMap(state.Get(mockStateKey)))
+		for _, key := range r.Exists {
+			if _, has := mockState.GetValue(state.ExpandAsText(key)); !has {
+				return false, nil
+			}
+		}
+	}
+	return criteria.Evaluate(nil, state, r.When, &r.whenEval, "Rule.When", true)
+}
+
+func isMatched(expected, actual interface{}, root string) (bool, error) {
+	validation, err := assertly.Assert(expected, actual, assertly.NewDataPath(root))
+	if err != nil {
+		return false, err
+	}
+	return !validation.HasFailure(), nil
+}
+
+// Mock represents dynamic mock rules with shared endpoint state
+type Mock struct {
+	Rules []*Rule
+	State data.Map
+	base  data.Map
+}
+
+// Match returns the first rule matching supplied request with request expansion state
+func (m *Mock) Match(request *http.Request) (*Rule, data.Map, error) {
+	if m == nil || len(m.Rules) == 0 {
+		return nil, nil, nil
+	}
+	state, err := m.requestState(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, rule := range m.Rules {
+		matched, err := rule.matches(request, state)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to match rule %v %v: %w", rule.Method, rule.Path, err)
+		}
+		if matched {
+			return rule, state, nil
+		}
+	}
+	return nil, nil, nil
+}
+
+// Handle applies rule state updates and writes expanded response
+func (m *Mock) Handle(writer http.ResponseWriter, rule *Rule, state data.Map) error {
+	for _, key := range rule.Delete {
+		deleteValue(m.State, state.ExpandAsText(key))
+	}
+	for key, value := range rule.Set {
+		m.State.SetValue(state.ExpandAsText(key), state.Expand(value))
+	}
+	var body []byte
+	contentType := ""
+	switch value := state.Expand(rule.Response.Body).(type) {
+	case nil:
+	case string:
+		body = []byte(value)
+	case []byte:
+		body = value
+	default:
+		normalized, err := toolbox.NormalizeKVPairs(value)
+		if err != nil {
+			return fmt.Errorf("failed to normalize response body: %w", err)
+		}
+		encoded, err := json.Marshal(normalized)
+		if err != nil {
+			return fmt.Errorf("failed to encode response body: %w", err)
+		}
+		body = encoded
+		contentType = "application/json"
+	}
+	for key, value := range rule.Response.Header {
+		writer.Header().Set(key, state.ExpandAsText(value))
+	}
+	if contentType != "" && writer.Header().Get(ContentTypeKey) == "" {
+		writer.Header().Set(ContentTypeKey, contentType)
+	}
+	writer.WriteHeader(rule.Response.Code)
+	if len(body) > 0 {
+		_, err := writer.Write(body)
+		return err
+	}
+	return nil
+}
+
+// requestState returns expansion state with request details, request body is restored for subsequent readers
+func (m *Mock) requestState(request *http.Request) (data.Map, error) {
+	var content []byte
+	if request.Body != nil {
+		var err error
+		if content, err = ioutil.ReadAll(request.Body); err != nil {
+			return nil, fmt.Errorf("failed to read body %v, %v", request.URL, err)
+		}
+		request.Body = ioutil.NopCloser(bytes.NewReader(content))
+	}
+	var state = data.NewMap()
+	for k, v := range m.base {
+		state[k] = v
+	}
+	var query = make(map[string]interface{})
+	for key, values := range request.URL.Query() {
+		query[key] = strings.Join(values, ",")
+	}
+	var header = make(map[string]interface{})
+	for key, values := range request.Header {
+		header[key] = strings.Join(values, ",")
+	}
+	var body interface{} = string(content)
+	if len(content) > 0 && toolbox.IsStructuredJSON(string(content)) {
+		var decoded interface{}
+		if err := json.Unmarshal(content, &decoded); err == nil {
+			body = decoded
+		}
+	}
+	state.Put(requestStateKey, map[string]interface{}{
+		"Method": request.Method,
+		"URL":    request.URL.String(),
+		"Path":   request.URL.Path,
+		"Body":   string(content),
+	})
+	state.Put(queryStateKey, query)
+	state.Put(headerStateKey, header)
+	state.Put(bodyStateKey, body)
+	state.Put(mockStateKey, m.State)
+	return state, nil
+}
+
+// deleteValue removes value for supplied dot separated key
+func deleteValue(state data.Map, key string) {
+	index := strings.LastIndex(key, ".")
+	if index == -1 {
+		state.Delete(key)
+		return
+	}
+	parent, ok := state.GetValue(key[:index])
+	if !ok {
+		return
+	}
+	switch aMap := parent.(type) {
+	case map[string]interface{}:
+		delete(aMap, key[index+1:])
+	case data.Map:
+		delete(aMap, key[index+1:])
+	}
+}
+
+// NewMock creates a new mock, base state is used for template expansion (i.e. UDFs, workflow variables)
+func NewMock(rules []*Rule, state map[string]interface{}, base data.Map) *Mock {
+	var result = &Mock{
+		Rules: rules,
+		State: data.NewMap(),
+		base:  base,
+	}
+	for k, v := range state {
+		result.State.Put(k, v)
+	}
+	return result
+}
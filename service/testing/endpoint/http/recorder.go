@@ -1,6 +1,7 @@
 package http
 
 import (
+	"crypto/x509"
 	"fmt"
 	"github.com/satori/go.uuid"
 	"github.com/viant/toolbox"
@@ -59,16 +60,46 @@ func StartRecorder(targetURLs ...string) error {
 			})
 	}
 	recorderBridge, err := bridge.StartRecordingBridge(port, outputDirectory, routes...)
+	if err != nil {
+		return err
+	}
 	if isSecure {
 		var serverCert = "server.crt"
 		var serverKey = "server.key"
-		if toolbox.FileExists(serverCert) {
+		if !toolbox.FileExists(serverCert) && !toolbox.FileExists(serverKey) {
+			if serverCert, serverKey, err = generateRecorderCert(outputDirectory); err != nil {
+				return err
+			}
+		}
+		if !toolbox.FileExists(serverCert) {
 			return fmt.Errorf("SSL server cert file does not exists %v", serverCert)
 		}
-		if toolbox.FileExists(serverCert) {
+		if !toolbox.FileExists(serverKey) {
 			return fmt.Errorf("SSL server key file does not exists %v", serverKey)
 		}
 		return recorderBridge.ListenAndServeTLS(serverCert, serverKey)
 	}
 	return recorderBridge.ListenAndServe()
 }
+
+// generateRecorderCert writes certificate signed by generated CA to output directory, CA is exported as ca.crt
+func generateRecorderCert(outputDirectory string) (string, string, error) {
+	authority, err := newCertAuthority()
+	if err != nil {
+		return "", "", err
+	}
+	certPEM, keyPEM, err := authority.issue("endly recorder", defaultTLSHosts, x509.ExtKeyUsageServerAuth)
+	if err != nil {
+		return "", "", err
+	}
+	var caCert = path.Join(outputDirectory, "ca.crt")
+	var serverCert = path.Join(outputDirectory, "server.crt")
+	var serverKey = path.Join(outputDirectory, "server.key")
+	for location, content := range map[string][]byte{caCert: authority.certPEM, serverCert: certPEM, serverKey: keyPEM} {
+		if err = writePEM(location, content); err != nil {
+			return "", "", err
+		}
+	}
+	log.Printf("generated self-signed server certificate, CA: %v", caCert)
+	return serverCert, serverKey, nil
+}
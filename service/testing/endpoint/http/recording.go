@@ -0,0 +1,154 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/viant/endly/internal/util"
+	"github.com/viant/toolbox/bridge"
+)
+
+// RedactedValue represents value replacing redacted header values
+const RedactedValue = "***"
+
+// DefaultRedactedHeaders represents headers redacted by default, Cookie is kept as it is a default replay index key
+var DefaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Set-Cookie"}
+
+// Recording represents recording reverse proxy, trips are written in ListenRequest replay format
+type Recording struct {
+	Directory        string
+	requestTemplate  string
+	responseTemplate string
+	redact           map[string]bool
+	server           *http.Server
+	mux              sync.Mutex
+	count            int32
+	err              error
+}
+
+// Count returns number of recorded trips
+func (r *Recording) Count() int {
+	return int(atomic.LoadInt32(&r.count))
+}
+
+// Stop shuts down recording proxy
+func (r *Recording) Stop() error {
+	if err := r.server.Shutdown(context.Background()); err != nil {
+		return err
+	}
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.err
+}
+
+// record writes redacted request and response
+func (r *Recording) record(request *http.Request, response *http.Response) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	var requestBody []byte
+	if request.Body != nil {
+		requestBody, _ = ioutil.ReadAll(request.Body)
+	}
+	responseBody, _ := ioutil.ReadAll(response.Body)
+	code := response.StatusCode
+	if code == 0 {
+		code = http.StatusOK
+	}
+	httpRequest := &bridge.HttpRequest{
+		Method: request.Method,
+		URL:    request.URL.String(),
+		Header: r.redacted(request.Header),
+		Body:   util.AsPayload(requestBody),
+	}
+	httpResponse := &bridge.HttpResponse{
+		Code:   code,
+		Header: r.redacted(response.Header),
+		Body:   util.AsPayload(responseBody),
+	}
+	index := int(r.count)
+	if err := r.write(fmt.Sprintf(r.requestTemplate, index), httpRequest); err != nil {
+		r.err = err
+		return
+	}
+	if err := r.write(fmt.Sprintf(r.responseTemplate, index), httpResponse); err != nil {
+		r.err = err
+		return
+	}
+	atomic.AddInt32(&r.count, 1)
+}
+
+func (r *Recording) redacted(header http.Header) http.Header {
+	var result = header.Clone()
+	for key := range result {
+		if r.redact[http.CanonicalHeaderKey(key)] {
+			result[key] = []string{RedactedValue}
+		}
+	}
+	return result
+}
+
+func (r *Recording) write(filename string, source interface{}) error {
+	data, err := json.MarshalIndent(source, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err = ioutil.WriteFile(path.Join(r.Directory, filename), data, 0644); err != nil {
+		return fmt.Errorf("failed to write recorded trip %v, %w", filename, err)
+	}
+	return nil
+}
+
+// StartRecording starts recording reverse proxy for supplied targets, target URL path is used as route pattern
+func StartRecording(port int, directory string, targets []*url.URL, redact []string, requestTemplate, responseTemplate string) (*Recording, error) {
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create recording directory %v, %w", directory, err)
+	}
+	var result = &Recording{
+		Directory:        directory,
+		requestTemplate:  requestTemplate,
+		responseTemplate: responseTemplate,
+		redact:           make(map[string]bool),
+	}
+	for _, header := range redact {
+		result.redact[http.CanonicalHeaderKey(header)] = true
+	}
+	mux := http.NewServeMux()
+	for _, target := range targets {
+		target := target
+		pattern := target.Path
+		if pattern == "" {
+			pattern = "/"
+		}
+		proxy := &httputil.ReverseProxy{
+			Director: func(request *http.Request) {
+				request.URL.Scheme = target.Scheme
+				request.URL.Host = target.Host
+				request.Host = target.Host
+			},
+		}
+		mux.Handle(pattern, bridge.NewListeningHandler(proxy, 2, 8*1024, result.record))
+	}
+	result.server = &http.Server{Addr: fmt.Sprintf(":%v", port), Handler: mux}
+	errorNotification := make(chan error, 1)
+	go func() {
+		errorNotification <- result.server.ListenAndServe()
+	}()
+	select {
+	case err := <-errorNotification:
+		if err != nil && err != http.ErrServerClosed {
+			return nil, fmt.Errorf("failed to start recording proxy on port %v, %v", port, err)
+		}
+	case <-time.After(time.Second):
+	}
+	return result, nil
+}
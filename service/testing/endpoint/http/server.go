@@ -18,6 +18,9 @@ type Server struct {
 	http.Server
 	*httpHandler
 	trips            map[string]*HTTPResponses
+	mock             *Mock
+	faults           *Faults
+	caPEM            []byte
 	mux              sync.Mutex
 	rotate           bool
 	indexKeys        []string
@@ -36,9 +39,30 @@ func (s *Server) Append(trips *HTTPServerTrips) {
 			trips.Trips[k] = v
 		}
 	}
+	if trips.Mock == nil {
+		trips.Mock = s.mock
+	}
+	if s.faults == nil {
+		s.faults = trips.Faults
+	} else if faults := trips.Faults.List(); len(faults) > 0 {
+		s.faults.Set(faults)
+	}
+	trips.Faults = s.faults
 	s.httpHandler.handler = getServerHandler(&s.Server, s.httpHandler, trips)
 }
 
+// Faults returns server faults
+func (s *Server) Faults() *Faults {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.faults
+}
+
+// CA returns generated CA certificate PEM, empty if server does not use generated certificates
+func (s *Server) CA() string {
+	return string(s.caPEM)
+}
+
 // StartServer starts http request, the server has ability to replay recorded  HTTP trips with https://github.com/viant/toolbox/blob/master/bridge/http_bridge_recording_util.go#L82
 func StartServer(port int, trips *HTTPServerTrips, reqTemplate, respTemplate string) (*Server, error) {
 	err := trips.Init(reqTemplate, respTemplate)
@@ -48,6 +72,7 @@ func StartServer(port int, trips *HTTPServerTrips, reqTemplate, respTemplate str
 
 	var httpHandler = &httpHandler{
 		running: 1,
+		journal: NewJournal(trips.JournalSize),
 	}
 
 	server := &Server{
@@ -55,16 +80,33 @@ func StartServer(port int, trips *HTTPServerTrips, reqTemplate, respTemplate str
 		indexKeys:        trips.IndexKeys,
 		httpHandler:      httpHandler,
 		trips:            trips.Trips,
+		mock:             trips.Mock,
+		faults:           trips.Faults,
 		Server:           http.Server{Addr: fmt.Sprintf(":%v", port), Handler: httpHandler},
 		requestTemplate:  reqTemplate,
 		responseTemplate: respTemplate,
 	}
 	httpHandler.handler = getServerHandler(&server.Server, httpHandler, trips)
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	if trips.TLS != nil {
+		if server.TLSConfig, server.caPEM, err = trips.TLS.Config(); err != nil {
+			return nil, fmt.Errorf("failed to start https server :%v, %v", port, err)
+		}
+		protocols.SetHTTP2(trips.HTTP2)
+	} else {
+		protocols.SetUnencryptedHTTP2(trips.HTTP2)
+	}
+	server.Protocols = &protocols
 
 	errorNotification := make(chan bool, 1)
 	go func() {
 		fmt.Printf("Starting server on %v\n", port)
-		err = server.Server.ListenAndServe()
+		if server.TLSConfig != nil {
+			err = server.Server.ListenAndServeTLS("", "")
+		} else {
+			err = server.Server.ListenAndServe()
+		}
 		atomic.StoreInt32(&httpHandler.running, 0)
 		errorNotification <- true
 		if err != nil {
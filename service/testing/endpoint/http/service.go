@@ -2,8 +2,14 @@ package http
 
 import (
 	"fmt"
+	"github.com/satori/go.uuid"
 	"github.com/viant/endly"
 	"github.com/viant/endly/model/location"
+	"github.com/viant/endly/service/testing/validator"
+	"github.com/viant/toolbox"
+	"net/url"
+	"os"
+	"path"
 	"strconv"
 )
 
@@ -15,7 +21,8 @@ const (
 // service represents http endpoint service, that has ability to replay HTTP trips
 type service struct {
 	*endly.AbstractService
-	servers map[int]*Server
+	servers    map[int]*Server
+	recordings map[int]*Recording
 }
 
 func (s *service) shutdown(context *endly.Context, req *ShutdownRequest) (interface{}, error) {
@@ -34,6 +41,13 @@ func (s *service) listen(context *endly.Context, request *ListenRequest) (*Liste
 	if request.BaseDirectory != "" {
 		request.BaseDirectory = location.NewResource(state.ExpandAsText(request.BaseDirectory)).Path()
 	}
+	if request.TLS != nil {
+		for _, file := range []*string{&request.TLS.CertFile, &request.TLS.KeyFile, &request.TLS.CAExport, &request.TLS.ClientCA, &request.TLS.ClientCertExport, &request.TLS.ClientKeyExport} {
+			if *file != "" {
+				*file = location.NewResource(state.ExpandAsText(*file)).Path()
+			}
+		}
+	}
 	key := ServiceID + ":" + strconv.Itoa(request.Port)
 	s.Mutex().Lock()
 	defer s.Mutex().Unlock()
@@ -46,6 +60,10 @@ func (s *service) listen(context *endly.Context, request *ListenRequest) (*Liste
 		}
 	}
 	trips := request.AsHTTPServerTrips()
+	if len(request.Rules) > 0 {
+		trips.Mock = NewMock(request.Rules, request.State, state.Clone())
+	}
+	trips.Faults = NewFaults(request.Faults)
 
 	server, err := StartServer(request.Port, trips, request.RequestTemplate, request.ResponseTemplate)
 	if err != nil {
@@ -55,11 +73,130 @@ func (s *service) listen(context *endly.Context, request *ListenRequest) (*Liste
 	s.servers[request.Port] = server
 	response = &ListenResponse{
 		Trips: trips.Trips,
+		CA:    server.CA(),
 	}
 	serviceState.Put(key, response)
 	return response, nil
 }
 
+func (s *service) server(port int) (*Server, error) {
+	s.Mutex().RLock()
+	defer s.Mutex().RUnlock()
+	server, ok := s.servers[port]
+	if !ok {
+		return nil, fmt.Errorf("server not started on port: %v", port)
+	}
+	return server, nil
+}
+
+func (s *service) requests(context *endly.Context, request *RequestsRequest) (*RequestsResponse, error) {
+	server, err := s.server(request.Port)
+	if err != nil {
+		return nil, err
+	}
+	requests := server.journal.Requests(request.Method, request.Path)
+	return &RequestsResponse{Count: len(requests), Requests: requests}, nil
+}
+
+func (s *service) assert(context *endly.Context, request *AssertRequest) (*AssertResponse, error) {
+	server, err := s.server(request.Port)
+	if err != nil {
+		return nil, err
+	}
+	requests := server.journal.Requests(request.Method, request.Path)
+	var recorded = make([]interface{}, 0, len(requests))
+	for _, req := range requests {
+		recorded = append(recorded, req.AsMap())
+	}
+	var actual interface{} = recorded
+	if toolbox.IsMap(request.Expect) {
+		actual = map[string]interface{}{
+			"Count":    len(requests),
+			"Requests": recorded,
+		}
+	}
+	response := &AssertResponse{Count: len(requests)}
+	response.AssertResponse, err = validator.Assert(context, request, request.Expect, actual, fmt.Sprintf("%v.requests", ServiceID), request.Description)
+	return response, err
+}
+
+func (s *service) clear(context *endly.Context, request *ClearRequest) (*ClearResponse, error) {
+	server, err := s.server(request.Port)
+	if err != nil {
+		return nil, err
+	}
+	return &ClearResponse{Cleared: server.journal.Clear()}, nil
+}
+
+func (s *service) fault(context *endly.Context, request *FaultRequest) (*FaultResponse, error) {
+	server, err := s.server(request.Port)
+	if err != nil {
+		return nil, err
+	}
+	faults := server.Faults()
+	faults.Set(request.Faults)
+	return &FaultResponse{Faults: faults.Len()}, nil
+}
+
+func (s *service) record(context *endly.Context, request *RecordRequest) (*RecordResponse, error) {
+	state := context.State()
+	directory := request.Directory
+	if directory == "" {
+		UUID, err := uuid.NewV1()
+		if err != nil {
+			return nil, err
+		}
+		currentDirectory, _ := os.Getwd()
+		directory = path.Join(currentDirectory, fmt.Sprintf("http_recording-%v", UUID.String()))
+	}
+	directory = location.NewResource(state.ExpandAsText(directory)).Path()
+	var targets = make([]*url.URL, 0, len(request.Targets))
+	for _, target := range request.Targets {
+		targetURL, err := url.Parse(state.ExpandAsText(target))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse target URL %v, %v", target, err)
+		}
+		targets = append(targets, targetURL)
+	}
+	s.Mutex().Lock()
+	if _, ok := s.recordings[request.Port]; ok {
+		s.Mutex().Unlock()
+		return nil, fmt.Errorf("recording already started on port: %v", request.Port)
+	}
+	s.recordings[request.Port] = nil //reserves port while proxy is starting
+	s.Mutex().Unlock()
+	recording, err := StartRecording(request.Port, directory, targets, request.Redact, request.RequestTemplate, request.ResponseTemplate)
+	s.Mutex().Lock()
+	defer s.Mutex().Unlock()
+	if err != nil {
+		delete(s.recordings, request.Port)
+		return nil, err
+	}
+	s.recordings[request.Port] = recording
+	return &RecordResponse{Port: request.Port, Directory: directory}, nil
+}
+
+func (s *service) stop(context *endly.Context, request *StopRequest) (*StopResponse, error) {
+	s.Mutex().Lock()
+	recording, ok := s.recordings[request.Port]
+	if ok && recording == nil {
+		s.Mutex().Unlock()
+		return nil, fmt.Errorf("recording is still starting on port: %v", request.Port)
+	}
+	delete(s.recordings, request.Port)
+	s.Mutex().Unlock()
+	if !ok {
+		return nil, fmt.Errorf("recording not started on port: %v", request.Port)
+	}
+	err := recording.Stop()
+	return &StopResponse{
+		Directory:        recording.Directory,
+		Trips:            recording.Count(),
+		RequestTemplate:  recording.requestTemplate,
+		ResponseTemplate: recording.responseTemplate,
+	}, err
+}
+
 func (s *service) registerRoutes() {
 	s.Register(&endly.Route{
 		Action: "listen",
@@ -114,6 +251,114 @@ func (s *service) registerRoutes() {
 				}
 				return nil, fmt.Errorf("unsupported request type: %T", request)
 			},
+		},
+		&endly.Route{
+			Action: "requests",
+			RequestInfo: &endly.ActionInfo{
+				Description: "return requests received by HTTP endpoint",
+			},
+			RequestProvider: func() interface{} {
+				return &RequestsRequest{}
+			},
+			ResponseProvider: func() interface{} {
+				return &RequestsResponse{}
+			},
+			Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+				if req, ok := request.(*RequestsRequest); ok {
+					return s.requests(context, req)
+				}
+				return nil, fmt.Errorf("unsupported request type: %T", request)
+			},
+		},
+		&endly.Route{
+			Action: "assert",
+			RequestInfo: &endly.ActionInfo{
+				Description: "assert requests received by HTTP endpoint",
+			},
+			RequestProvider: func() interface{} {
+				return &AssertRequest{}
+			},
+			ResponseProvider: func() interface{} {
+				return &AssertResponse{}
+			},
+			Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+				if req, ok := request.(*AssertRequest); ok {
+					return s.assert(context, req)
+				}
+				return nil, fmt.Errorf("unsupported request type: %T", request)
+			},
+		},
+		&endly.Route{
+			Action: "clear",
+			RequestInfo: &endly.ActionInfo{
+				Description: "clear requests received by HTTP endpoint",
+			},
+			RequestProvider: func() interface{} {
+				return &ClearRequest{}
+			},
+			ResponseProvider: func() interface{} {
+				return &ClearResponse{}
+			},
+			Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+				if req, ok := request.(*ClearRequest); ok {
+					return s.clear(context, req)
+				}
+				return nil, fmt.Errorf("unsupported request type: %T", request)
+			},
+		},
+		&endly.Route{
+			Action: "fault",
+			RequestInfo: &endly.ActionInfo{
+				Description: "replace faults injected by HTTP endpoint",
+			},
+			RequestProvider: func() interface{} {
+				return &FaultRequest{}
+			},
+			ResponseProvider: func() interface{} {
+				return &FaultResponse{}
+			},
+			Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+				if req, ok := request.(*FaultRequest); ok {
+					return s.fault(context, req)
+				}
+				return nil, fmt.Errorf("unsupported request type: %T", request)
+			},
+		},
+		&endly.Route{
+			Action: "record",
+			RequestInfo: &endly.ActionInfo{
+				Description: "start recording reverse proxy writing trips in listen replay format",
+			},
+			RequestProvider: func() interface{} {
+				return &RecordRequest{}
+			},
+			ResponseProvider: func() interface{} {
+				return &RecordResponse{}
+			},
+			Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+				if req, ok := request.(*RecordRequest); ok {
+					return s.record(context, req)
+				}
+				return nil, fmt.Errorf("unsupported request type: %T", request)
+			},
+		},
+		&endly.Route{
+			Action: "stop",
+			RequestInfo: &endly.ActionInfo{
+				Description: "stop recording reverse proxy, returns capture location",
+			},
+			RequestProvider: func() interface{} {
+				return &StopRequest{}
+			},
+			ResponseProvider: func() interface{} {
+				return &StopResponse{}
+			},
+			Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+				if req, ok := request.(*StopRequest); ok {
+					return s.stop(context, req)
+				}
+				return nil, fmt.Errorf("unsupported request type: %T", request)
+			},
 		})
 }
 
@@ -123,6 +368,7 @@ func (s *service) registerRoutes() {
 func New() endly.Service {
 	var result = &service{
 		servers:         make(map[int]*Server),
+		recordings:      make(map[int]*Recording),
 		AbstractService: endly.NewAbstractService(ServiceID),
 	}
 	result.AbstractService.Service = result
@@ -1,14 +1,18 @@
 package http_test
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/endly"
 	endpoint "github.com/viant/endly/service/testing/endpoint/http"
 	"github.com/viant/toolbox"
+	"io/ioutil"
 	"net/http"
 	"path"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestHTTPEndpointService_Run(t *testing.T) {
@@ -68,3 +72,349 @@ func TestHTTPEndpointService_Run_WithError(t *testing.T) {
 	}
 
 }
+
+func TestHTTPEndpointService_Rules(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	service, _ := context.Service(endpoint.ServiceID)
+
+	response := service.Run(context, &endpoint.ListenRequest{
+		Port:  7719,
+		State: map[string]interface{}{"users": map[string]interface{}{}},
+		Rules: []*endpoint.Rule{
+			{
+				Method: "POST",
+				Path:   "/users",
+				Body:   map[string]interface{}{"name": "~/.+/"},
+				Set:    map[string]interface{}{"users.${body.id}": "$body"},
+				Response: &endpoint.RuleResponse{
+					Code: 201,
+					Body: map[string]interface{}{"id": "$body.id", "status": "created"},
+				},
+			},
+			{
+				Method:   "GET",
+				Path:     "/users/{id}",
+				Exists:   []string{"users.${path.id}"},
+				Response: &endpoint.RuleResponse{Body: "${state.users.${path.id}}"},
+			},
+			{
+				Method:   "DELETE",
+				Path:     "/users/{id}",
+				Delete:   []string{"users.${path.id}"},
+				Response: &endpoint.RuleResponse{Code: 204},
+			},
+			{
+				Path:     "/echo/*",
+				Header:   map[string]interface{}{"X-Test": "abc"},
+				Response: &endpoint.RuleResponse{Body: "$request.Method $path.suffix $query.q"},
+			},
+		},
+	})
+	if !assert.Equal(t, "", response.Error) {
+		return
+	}
+	call := func(method, URL, body string, header map[string]string) (int, string) {
+		request, _ := http.NewRequest(method, "http://127.0.0.1:7719"+URL, strings.NewReader(body))
+		for k, v := range header {
+			request.Header.Set(k, v)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if !assert.Nil(t, err) {
+			return 0, ""
+		}
+		defer response.Body.Close()
+		content, _ := ioutil.ReadAll(response.Body)
+		return response.StatusCode, string(content)
+	}
+
+	code, body := call("POST", "/users", `{"id":"1","name":"Bob"}`, nil)
+	assert.Equal(t, 201, code)
+	assert.JSONEq(t, `{"id":"1","status":"created"}`, body)
+	code, _ = call("POST", "/users", `{"id":"2"}`, nil)
+	assert.Equal(t, 404, code)
+
+	code, body = call("GET", "/users/1", "", nil)
+	assert.Equal(t, 200, code)
+	assert.JSONEq(t, `{"id":"1","name":"Bob"}`, body)
+
+	code, _ = call("DELETE", "/users/1", "", nil)
+	assert.Equal(t, 204, code)
+	code, _ = call("GET", "/users/1", "", nil)
+	assert.Equal(t, 404, code)
+
+	code, body = call("PUT", "/echo/a/b?q=1", "", map[string]string{"X-Test": "abc"})
+	assert.Equal(t, 200, code)
+	assert.Equal(t, "PUT a/b 1", body)
+}
+
+func TestHTTPEndpointService_Journal(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	service, _ := context.Service(endpoint.ServiceID)
+
+	response := service.Run(context, &endpoint.ListenRequest{
+		Port:  7720,
+		Rules: []*endpoint.Rule{{Path: "/users/*"}},
+	})
+	if !assert.Equal(t, "", response.Error) {
+		return
+	}
+	_, err := http.Post("http://127.0.0.1:7720/users", "application/json", strings.NewReader(`{"name":"Bob"}`))
+	assert.Nil(t, err)
+	_, err = http.Get("http://127.0.0.1:7720/users/1?x=1")
+	assert.Nil(t, err)
+	_, err = http.Get("http://127.0.0.1:7720/orders")
+	assert.Nil(t, err)
+
+	response = service.Run(context, &endpoint.RequestsRequest{Port: 7720, Path: "/users/*"})
+	if assert.Equal(t, "", response.Error) {
+		requestsResponse := response.Response.(*endpoint.RequestsResponse)
+		if assert.Equal(t, 2, requestsResponse.Count) {
+			assert.Equal(t, "POST", requestsResponse.Requests[0].Method)
+			assert.EqualValues(t, map[string]interface{}{"name": "Bob"}, requestsResponse.Requests[0].JSONBody)
+			assert.Equal(t, "1", requestsResponse.Requests[1].Query["x"])
+			assert.Equal(t, 200, requestsResponse.Requests[1].Code)
+		}
+	}
+
+	response = service.Run(context, &endpoint.AssertRequest{
+		Port: 7720,
+		Expect: []interface{}{
+			map[string]interface{}{"Method": "POST", "Path": "/users", "JSONBody": map[string]interface{}{"name": "Bob"}},
+			map[string]interface{}{"Method": "GET", "Path": "/users/1"},
+			map[string]interface{}{"Path": "/orders", "Code": 404},
+		},
+	})
+	if assert.Equal(t, "", response.Error) {
+		assertResponse := response.Response.(*endpoint.AssertResponse)
+		assert.Equal(t, 3, assertResponse.Count)
+		assert.Equal(t, 0, assertResponse.FailedCount, assertResponse.Report())
+	}
+
+	response = service.Run(context, &endpoint.AssertRequest{
+		Port:   7720,
+		Method: "GET",
+		Expect: map[string]interface{}{"Count": 1},
+	})
+	if assert.Equal(t, "", response.Error) {
+		assertResponse := response.Response.(*endpoint.AssertResponse)
+		assert.Equal(t, 1, assertResponse.FailedCount)
+	}
+
+	response = service.Run(context, &endpoint.ClearRequest{Port: 7720})
+	if assert.Equal(t, "", response.Error) {
+		assert.Equal(t, 3, response.Response.(*endpoint.ClearResponse).Cleared)
+	}
+	response = service.Run(context, &endpoint.RequestsRequest{Port: 7720})
+	assert.Equal(t, 0, response.Response.(*endpoint.RequestsResponse).Count)
+}
+
+func TestHTTPEndpointService_Fault(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	service, _ := context.Service(endpoint.ServiceID)
+
+	response := service.Run(context, &endpoint.ListenRequest{
+		Port: 7721,
+		Rules: []*endpoint.Rule{
+			{Path: "/items", Response: &endpoint.RuleResponse{Body: "0123456789"}},
+			{Path: "/truncated", Response: &endpoint.RuleResponse{Body: "0123456789"}},
+			{Path: "/slow", Response: &endpoint.RuleResponse{Body: "ok"}},
+		},
+		Faults: []*endpoint.Fault{
+			{Path: "/items", StatusCode: 503, Calls: []int{2}},
+			{Path: "/reset", Reset: true},
+			{Path: "/truncated", Truncate: 3},
+			{Path: "/slow", Latency: &endpoint.Latency{Ms: 200}},
+		},
+	})
+	if !assert.Equal(t, "", response.Error) {
+		return
+	}
+	var codes = make([]int, 0)
+	for i := 0; i < 3; i++ {
+		httpResponse, err := http.Get("http://127.0.0.1:7721/items")
+		if assert.Nil(t, err) {
+			codes = append(codes, httpResponse.StatusCode)
+		}
+	}
+	assert.EqualValues(t, []int{200, 503, 200}, codes)
+
+	_, err := http.Get("http://127.0.0.1:7721/reset")
+	assert.NotNil(t, err)
+
+	started := time.Now()
+	_, err = http.Get("http://127.0.0.1:7721/slow")
+	assert.Nil(t, err)
+	assert.True(t, time.Since(started) >= 200*time.Millisecond)
+
+	httpResponse, err := http.Get("http://127.0.0.1:7721/truncated")
+	if assert.Nil(t, err) {
+		_, err = ioutil.ReadAll(httpResponse.Body)
+		assert.NotNil(t, err)
+	}
+
+	response = service.Run(context, &endpoint.FaultRequest{Port: 7721})
+	if assert.Equal(t, "", response.Error) {
+		assert.Equal(t, 0, response.Response.(*endpoint.FaultResponse).Faults)
+	}
+	started = time.Now()
+	httpResponse, err = http.Get("http://127.0.0.1:7721/slow")
+	if assert.Nil(t, err) {
+		body, err := ioutil.ReadAll(httpResponse.Body)
+		assert.Nil(t, err)
+		assert.Equal(t, "ok", string(body))
+	}
+	assert.True(t, time.Since(started) < 200*time.Millisecond)
+	httpResponse, err = http.Get("http://127.0.0.1:7721/truncated")
+	if assert.Nil(t, err) {
+		body, err := ioutil.ReadAll(httpResponse.Body)
+		assert.Nil(t, err)
+		assert.Equal(t, "0123456789", string(body))
+	}
+}
+
+func TestHTTPEndpointService_TLS(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	service, _ := context.Service(endpoint.ServiceID)
+	baseDir := t.TempDir()
+
+	response := service.Run(context, &endpoint.ListenRequest{
+		Port:  7722,
+		HTTP2: true,
+		Rules: []*endpoint.Rule{{Path: "/ping", Response: &endpoint.RuleResponse{Body: "pong"}}},
+		TLS: &endpoint.TLS{
+			CAExport:         path.Join(baseDir, "ca.pem"),
+			ClientAuth:       true,
+			ClientCertExport: path.Join(baseDir, "client.crt"),
+			ClientKeyExport:  path.Join(baseDir, "client.key"),
+		},
+	})
+	if !assert.Equal(t, "", response.Error) {
+		return
+	}
+	listenResponse := response.Response.(*endpoint.ListenResponse)
+	caPEM, err := ioutil.ReadFile(path.Join(baseDir, "ca.pem"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, listenResponse.CA, string(caPEM))
+	rootCAs := x509.NewCertPool()
+	assert.True(t, rootCAs.AppendCertsFromPEM(caPEM))
+
+	client := &http.Client{Transport: &http.Transport{ForceAttemptHTTP2: true, TLSClientConfig: &tls.Config{RootCAs: rootCAs}}}
+	_, err = client.Get("https://localhost:7722/ping")
+	assert.NotNil(t, err, "client certificate should be required")
+
+	clientCert, err := tls.LoadX509KeyPair(path.Join(baseDir, "client.crt"), path.Join(baseDir, "client.key"))
+	if !assert.Nil(t, err) {
+		return
+	}
+	client = &http.Client{Transport: &http.Transport{ForceAttemptHTTP2: true, TLSClientConfig: &tls.Config{RootCAs: rootCAs, Certificates: []tls.Certificate{clientCert}}}}
+	httpResponse, err := client.Get("https://127.0.0.1:7722/ping")
+	if assert.Nil(t, err) {
+		body, _ := ioutil.ReadAll(httpResponse.Body)
+		assert.Equal(t, "pong", string(body))
+		assert.Equal(t, 2, httpResponse.ProtoMajor)
+	}
+}
+
+func TestHTTPEndpointService_Record(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	service, _ := context.Service(endpoint.ServiceID)
+
+	response := service.Run(context, &endpoint.ListenRequest{
+		Port:  7723,
+		Rules: []*endpoint.Rule{{Path: "/users/{id}", Response: &endpoint.RuleResponse{Body: "user ${path.id}"}}},
+	})
+	if !assert.Equal(t, "", response.Error) {
+		return
+	}
+	response = service.Run(context, &endpoint.RecordRequest{
+		Port:      7724,
+		Targets:   []string{"http://127.0.0.1:7723/"},
+		Directory: path.Join(t.TempDir(), "recording"),
+	})
+	if !assert.Equal(t, "", response.Error) {
+		return
+	}
+	request, _ := http.NewRequest("GET", "http://127.0.0.1:7724/users/1", nil)
+	request.Header.Set("Authorization", "Bearer secret")
+	request.Header.Set("Cookie", "session=1")
+	httpResponse, err := http.DefaultClient.Do(request)
+	if assert.Nil(t, err) {
+		body, _ := ioutil.ReadAll(httpResponse.Body)
+		assert.Equal(t, "user 1", string(body))
+	}
+
+	response = service.Run(context, &endpoint.RecordRequest{
+		Port:    7724,
+		Targets: []string{"http://127.0.0.1:7723/"},
+	})
+	assert.Contains(t, response.Error, "recording already started")
+
+	response = service.Run(context, &endpoint.StopRequest{Port: 7724})
+	if !assert.Equal(t, "", response.Error) {
+		return
+	}
+	stopResponse := response.Response.(*endpoint.StopResponse)
+	assert.Equal(t, 1, stopResponse.Trips)
+	recorded, err := ioutil.ReadFile(path.Join(stopResponse.Directory, "00-req.json"))
+	if assert.Nil(t, err) {
+		assert.False(t, strings.Contains(string(recorded), "secret"))
+		assert.True(t, strings.Contains(string(recorded), endpoint.RedactedValue))
+	}
+
+	response = service.Run(context, &endpoint.ListenRequest{
+		Port:          7725,
+		BaseDirectory: stopResponse.Directory,
+	})
+	if !assert.Equal(t, "", response.Error) {
+		return
+	}
+	request, _ = http.NewRequest("GET", "http://127.0.0.1:7725/users/1", nil)
+	request.Header.Set("Cookie", "session=1") //cookie is a default index key, it is not redacted by default
+	httpResponse, err = http.DefaultClient.Do(request)
+	if assert.Nil(t, err) {
+		body, _ := ioutil.ReadAll(httpResponse.Body)
+		assert.Equal(t, "user 1", string(body))
+	}
+}
+
+func TestFaults_List(t *testing.T) {
+	faults := endpoint.NewFaults([]*endpoint.Fault{{Path: "/items", StatusCode: 503}})
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 100; i++ {
+			faults.Set([]*endpoint.Fault{{Path: "/reset", Reset: true}})
+		}
+		close(done)
+	}()
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, 1, len(faults.List()))
+	}
+	<-done
+	list := faults.List()
+	list[0] = nil
+	assert.NotNil(t, faults.List()[0])
+	assert.Nil(t, (*endpoint.Faults)(nil).List())
+}
+
+func TestJournal_Record(t *testing.T) {
+	journal := endpoint.NewJournal(2)
+	for _, URI := range []string{"/a", "/b", "/c"} {
+		request, _ := http.NewRequest("GET", "http://127.0.0.1"+URI, nil)
+		journal.Record(request)
+	}
+	requests := journal.Requests("", "")
+	if assert.Equal(t, 2, len(requests)) {
+		assert.Equal(t, "/b", requests[0].Path)
+		assert.Equal(t, 1, requests[0].Index)
+		assert.Equal(t, "/c", requests[1].Path)
+		assert.Equal(t, 2, requests[1].Index)
+	}
+	assert.Equal(t, 2, journal.Clear())
+}
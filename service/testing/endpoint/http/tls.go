@@ -0,0 +1,203 @@
+package http
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path"
+	"time"
+)
+
+var defaultTLSHosts = []string{"localhost", "127.0.0.1", "::1"}
+
+// TLS represents HTTP endpoint TLS settings
+type TLS struct {
+	CertFile         string   `description:"server certificate PEM file, if empty certificate signed by generated self-signed CA is used"`
+	KeyFile          string   `description:"server private key PEM file"`
+	Hosts            []string `description:"generated server certificate host names and IPs, default: localhost, 127.0.0.1, ::1"`
+	CAExport         string   `description:"location generated CA certificate PEM is written to, so that the system under test can trust it"`
+	ClientAuth       bool     `description:"flag to require and verify client certificate (mTLS)"`
+	ClientCA         string   `description:"client CA certificate PEM file used to verify client certificates, generated CA is used if empty"`
+	ClientCertExport string   `description:"location client certificate PEM signed by generated CA is written to"`
+	ClientKeyExport  string   `description:"location client private key PEM is written to"`
+}
+
+// Init initialises TLS settings
+func (t *TLS) Init() error {
+	if len(t.Hosts) == 0 {
+		t.Hosts = defaultTLSHosts
+	}
+	return nil
+}
+
+// Validate checks if TLS settings are valid
+func (t *TLS) Validate() error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("both certFile and keyFile have to be specified")
+	}
+	if (t.ClientCertExport == "") != (t.ClientKeyExport == "") {
+		return fmt.Errorf("both clientCertExport and clientKeyExport have to be specified")
+	}
+	return nil
+}
+
+// Config returns server TLS config with CA certificate PEM (if CA was generated), generated certificates are exported when requested
+func (t *TLS) Config() (*tls.Config, []byte, error) {
+	var authority *certAuthority
+	var err error
+	if t.CertFile == "" || (t.ClientAuth && t.ClientCA == "") || t.ClientCertExport != "" {
+		if authority, err = newCertAuthority(); err != nil {
+			return nil, nil, err
+		}
+		if t.CAExport != "" {
+			if err = writePEM(t.CAExport, authority.certPEM); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	var certificate tls.Certificate
+	if t.CertFile != "" {
+		if certificate, err = tls.LoadX509KeyPair(t.CertFile, t.KeyFile); err != nil {
+			return nil, nil, fmt.Errorf("failed to load server certificate %v, %w", t.CertFile, err)
+		}
+	} else {
+		certPEM, keyPEM, err := authority.issue("endly endpoint", t.Hosts, x509.ExtKeyUsageServerAuth)
+		if err != nil {
+			return nil, nil, err
+		}
+		if certificate, err = tls.X509KeyPair(certPEM, keyPEM); err != nil {
+			return nil, nil, err
+		}
+	}
+	config := &tls.Config{Certificates: []tls.Certificate{certificate}}
+	if t.ClientCertExport != "" {
+		certPEM, keyPEM, err := authority.issue("endly client", nil, x509.ExtKeyUsageClientAuth)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err = writePEM(t.ClientCertExport, certPEM); err != nil {
+			return nil, nil, err
+		}
+		if err = writePEM(t.ClientKeyExport, keyPEM); err != nil {
+			return nil, nil, err
+		}
+	}
+	if t.ClientAuth {
+		clientCAs := x509.NewCertPool()
+		if t.ClientCA != "" {
+			caPEM, err := ioutil.ReadFile(t.ClientCA)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to read client CA %v, %w", t.ClientCA, err)
+			}
+			if !clientCAs.AppendCertsFromPEM(caPEM) {
+				return nil, nil, fmt.Errorf("invalid client CA: %v", t.ClientCA)
+			}
+		} else {
+			clientCAs.AddCert(authority.cert)
+		}
+		config.ClientCAs = clientCAs
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	if authority == nil {
+		return config, nil, nil
+	}
+	return config, authority.certPEM, nil
+}
+
+// certAuthority represents generated self-signed certificate authority
+type certAuthority struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+}
+
+// issue returns certificate and private key PEM signed by the authority
+func (a *certAuthority) issue(commonName string, hosts []string, usage x509.ExtKeyUsage) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate key, %w", err)
+	}
+	template, err := newCertTemplate(commonName)
+	if err != nil {
+		return nil, nil, err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{usage}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, a.cert, &key.PublicKey, a.key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create %v certificate, %w", commonName, err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), nil
+}
+
+func newCertTemplate(commonName string) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number, %w", err)
+	}
+	return &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName, Organization: []string{"endly"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		BasicConstraintsValid: true,
+	}, nil
+}
+
+// newCertAuthority generates a new self-signed certificate authority
+func newCertAuthority() (*certAuthority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate CA key, %w", err)
+	}
+	template, err := newCertTemplate("endly CA")
+	if err != nil {
+		return nil, err
+	}
+	template.IsCA = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA certificate, %w", err)
+	}
+	cert, err := x509.ParseCertificate(certDER)
+	if err != nil {
+		return nil, err
+	}
+	return &certAuthority{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}),
+	}, nil
+}
+
+func writePEM(location string, content []byte) error {
+	if err := os.MkdirAll(path.Dir(location), 0755); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(location, content, 0600); err != nil {
+		return fmt.Errorf("failed to write %v, %w", location, err)
+	}
+	return nil
+}
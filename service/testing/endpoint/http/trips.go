@@ -13,6 +13,11 @@ type HTTPServerTrips struct {
 	Trips         map[string]*HTTPResponses
 	IndexKeys     []string
 	Mutex         *sync.Mutex
+	Mock          *Mock
+	Faults        *Faults
+	TLS           *TLS
+	HTTP2         bool
+	JournalSize   int
 }
 
 func (t *HTTPServerTrips) loadTripsIfNeeded(reqTemplate string, respTemplate string) error {
@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"errors"
+
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/service/testing/validator"
+)
+
+// CallRequest represents gRPC call request
+type CallRequest struct {
+	*model.Repeater
+	Target         string            `required:"true" description:"server address, i.e. 127.0.0.1:9090"`
+	Method         string            `required:"true" description:"gRPC method, i.e. pkg.Service/Method"`
+	ProtoFiles     []string          `description:".proto files defining called service, server reflection is used if protoFiles and descriptorSets are empty"`
+	ImportPaths    []string          `description:".proto import paths, proto file directory is used if empty"`
+	DescriptorSets []string          `description:"descriptor set files (protoc --descriptor_set_out --include_imports)"`
+	Request        interface{}       `description:"JSON request message (map or text), list of messages for client streaming method"`
+	Metadata       map[string]string `description:"request metadata"`
+	DeadlineMs     int               `description:"call deadline in ms, default 30000"`
+	TLS            bool              `description:"flag to use TLS transport"`
+	CAFile         string            `description:"CA certificate PEM file used to verify server with TLS, system roots are used if empty"`
+	Expect         interface{}       `description:"if specified, response (Code, Message, Header, Trailer, Response, Responses) is validated"`
+}
+
+// Init initialises request
+func (r *CallRequest) Init() error {
+	if r.DeadlineMs == 0 {
+		r.DeadlineMs = 30000
+	}
+	return nil
+}
+
+// Validate checks if request is valid
+func (r *CallRequest) Validate() error {
+	if r.Target == "" {
+		return errors.New("target was empty")
+	}
+	if r.Method == "" {
+		return errors.New("method was empty")
+	}
+	return nil
+}
+
+// CallResponse represents gRPC call response
+type CallResponse struct {
+	Code        string
+	Message     string                 `json:",omitempty"`
+	Header      map[string]interface{} `json:",omitempty"`
+	Trailer     map[string]interface{} `json:",omitempty"`
+	Response    map[string]interface{} `json:",omitempty" description:"response message, the last message for server streaming method"`
+	Responses   []interface{}          `json:",omitempty" description:"server streaming response messages"`
+	TimeTakenMs int
+	Data        map[string]interface{}    `json:",omitempty" description:"extracted data"`
+	Assert      *validator.AssertResponse `json:",omitempty"`
+}
+
+// AsMap returns response fields used by extraction and validation
+func (r *CallResponse) AsMap() map[string]interface{} {
+	var result = map[string]interface{}{
+		"Code":        r.Code,
+		"Message":     r.Message,
+		"Header":      r.Header,
+		"Trailer":     r.Trailer,
+		"Response":    r.Response,
+		"TimeTakenMs": r.TimeTakenMs,
+	}
+	if len(r.Responses) > 0 {
+		result["Responses"] = r.Responses
+	}
+	return result
+}
@@ -0,0 +1,41 @@
+package grpc
+
+import (
+	"fmt"
+
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+)
+
+// Messages returns messages
+func (r *CallRequest) Messages() []*msg.Message {
+	var response = make([]*msg.Message, 0)
+	response = append(response, msg.NewMessage(msg.NewStyled(fmt.Sprintf("%v %v", r.Target, r.Method), msg.MessageStyleGeneric), msg.NewStyled("grpc.Call", msg.MessageStyleGeneric)))
+	if r.Request != nil {
+		requestJSON, _ := toolbox.AsJSONText(r.Request)
+		response = append(response, msg.NewMessage(msg.NewStyled("Request", msg.MessageStyleGeneric), msg.NewStyled("grpc.Call", msg.MessageStyleGeneric),
+			msg.NewStyled(requestJSON, msg.MessageStyleInput),
+		))
+	}
+	return response
+}
+
+// Messages returns messages
+func (r *CallResponse) Messages() []*msg.Message {
+	var response = make([]*msg.Message, 0)
+	responseJSON, _ := toolbox.AsJSONText(r.AsMap())
+	response = append(response, msg.NewMessage(msg.NewStyled(fmt.Sprintf("Code: %v, time taken: %v ms", r.Code, r.TimeTakenMs), msg.MessageStyleGeneric), msg.NewStyled("grpc.Response", msg.MessageStyleGeneric),
+		msg.NewStyled(responseJSON, msg.MessageStyleOutput),
+	))
+	return response
+}
+
+// IsInput returns this request (CLI reporter interface)
+func (r *CallRequest) IsInput() bool {
+	return true
+}
+
+// IsOutput returns this response (CLI reporter interface)
+func (r *CallResponse) IsOutput() bool {
+	return true
+}
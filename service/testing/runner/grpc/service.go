@@ -0,0 +1,264 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/jhump/protoreflect/grpcreflect"
+	"github.com/viant/endly"
+	"github.com/viant/endly/internal/protoset"
+	"github.com/viant/endly/model/location"
+	"github.com/viant/endly/service/testing/validator"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// ServiceID represents gRPC runner service id.
+const ServiceID = "grpc/runner"
+
+type service struct {
+	*endly.AbstractService
+}
+
+func (s *service) call(context *endly.Context, request *CallRequest) (*CallResponse, error) {
+	var state = context.State()
+	conn, err := s.dial(state.ExpandAsText(request.Target), request.TLS, expandLocation(state.ExpandAsText, request.CAFile))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	method, err := s.method(conn, request, state.ExpandAsText)
+	if err != nil {
+		return nil, err
+	}
+	var messages []interface{}
+	if method.IsStreamingClient() {
+		values, ok := state.Expand(request.Request).([]interface{})
+		if !ok && request.Request != nil {
+			return nil, fmt.Errorf("client streaming method %v expects list of request messages", method.FullName())
+		}
+		messages = values
+	} else {
+		messages = []interface{}{state.Expand(request.Request)}
+	}
+	var requests = make([]proto.Message, 0, len(messages))
+	for _, message := range messages {
+		encoded, err := protoset.NewMessage(method.Input(), message)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, encoded)
+	}
+	var header = metadata.MD{}
+	for key, value := range request.Metadata {
+		header.Append(key, state.ExpandAsText(value))
+	}
+
+	response := &CallResponse{Data: make(map[string]interface{})}
+	repeater := request.Repeater.Init()
+	handler := func() (interface{}, error) {
+		*response = CallResponse{Data: response.Data}
+		s.invoke(context.Background(), conn, method, header, requests, request.DeadlineMs, response)
+		return response.AsMap(), nil
+	}
+	if err = repeater.Run(context, "GRPCRunner", s.AbstractService, handler, response.Data); err != nil {
+		return response, err
+	}
+	if request.Expect != nil {
+		response.Assert, err = validator.Assert(context, request, request.Expect, response.AsMap(), "GRPC.response", "assert gRPC response")
+	}
+	return response, err
+}
+
+// invoke calls RPC, status errors are reported with response code and message, parent carries workflow node deadline
+func (s *service) invoke(parent context.Context, conn *grpc.ClientConn, method protoreflect.MethodDescriptor, header metadata.MD, requests []proto.Message, deadlineMs int, response *CallResponse) {
+	ctx, cancel := context.WithTimeout(metadata.NewOutgoingContext(parent, header), time.Duration(deadlineMs)*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	streamDesc := &grpc.StreamDesc{ServerStreams: method.IsStreamingServer(), ClientStreams: method.IsStreamingClient()}
+	stream, err := conn.NewStream(ctx, streamDesc, protoset.FullMethod(method))
+	if err == nil {
+		err = s.exchange(stream, method, requests, response)
+	}
+	response.TimeTakenMs = int(time.Since(started) / time.Millisecond)
+	if stream != nil {
+		if received, headerErr := stream.Header(); headerErr == nil {
+			response.Header = asMap(received)
+		}
+		response.Trailer = asMap(stream.Trailer())
+	}
+	callStatus := status.Convert(err)
+	response.Code = callStatus.Code().String()
+	response.Message = callStatus.Message()
+}
+
+func (s *service) exchange(stream grpc.ClientStream, method protoreflect.MethodDescriptor, requests []proto.Message, response *CallResponse) error {
+	for _, request := range requests {
+		if err := stream.SendMsg(request); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return err
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	for {
+		message := dynamicpb.NewMessage(method.Output())
+		if err := stream.RecvMsg(message); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		decoded, err := protoset.AsMap(message)
+		if err != nil {
+			return err
+		}
+		response.Response = decoded
+		if !method.IsStreamingServer() {
+			return nil
+		}
+		response.Responses = append(response.Responses, decoded)
+	}
+}
+
+// method returns method descriptor loaded from proto files, descriptor sets or server reflection
+func (s *service) method(conn *grpc.ClientConn, request *CallRequest, expand func(text string) string) (protoreflect.MethodDescriptor, error) {
+	if len(request.ProtoFiles) > 0 || len(request.DescriptorSets) > 0 {
+		files, err := protoset.Load(expandLocations(expand, request.ProtoFiles), expandLocations(expand, request.ImportPaths), expandLocations(expand, request.DescriptorSets))
+		if err != nil {
+			return nil, err
+		}
+		return protoset.FindMethod(files, request.Method)
+	}
+	serviceName, methodName, err := protoset.ParseMethod(request.Method)
+	if err != nil {
+		return nil, err
+	}
+	client := grpcreflect.NewClientAuto(context.Background(), conn)
+	defer client.Reset()
+	serviceDescriptor, err := client.ResolveService(serviceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve service %v with server reflection, %w", serviceName, err)
+	}
+	methodDescriptor := serviceDescriptor.FindMethodByName(methodName)
+	if methodDescriptor == nil {
+		return nil, fmt.Errorf("method %v not found in service %v", methodName, serviceName)
+	}
+	return methodDescriptor.UnwrapMethod(), nil
+}
+
+func (s *service) dial(target string, useTLS bool, caFile string) (*grpc.ClientConn, error) {
+	transport := insecure.NewCredentials()
+	if useTLS {
+		config := &tls.Config{}
+		if caFile != "" {
+			caPEM, err := ioutil.ReadFile(caFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA %v, %w", caFile, err)
+			}
+			config.RootCAs = x509.NewCertPool()
+			if !config.RootCAs.AppendCertsFromPEM(caPEM) {
+				return nil, fmt.Errorf("invalid CA: %v", caFile)
+			}
+		}
+		transport = credentials.NewTLS(config)
+	}
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(transport))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %v, %w", target, err)
+	}
+	return conn, nil
+}
+
+func asMap(md metadata.MD) map[string]interface{} {
+	var result = make(map[string]interface{})
+	for key, values := range md {
+		result[key] = strings.Join(values, ",")
+	}
+	return result
+}
+
+func expandLocation(expand func(text string) string, loc string) string {
+	if loc == "" {
+		return ""
+	}
+	return location.NewResource(expand(loc)).Path()
+}
+
+func expandLocations(expand func(text string) string, locations []string) []string {
+	var result = make([]string, 0, len(locations))
+	for _, loc := range locations {
+		result = append(result, expandLocation(expand, loc))
+	}
+	return result
+}
+
+const grpcCallExample = `{
+  "Target": "127.0.0.1:9090",
+  "Method": "greeter.Greeter/SayHello",
+  "ProtoFiles": ["proto/greeter.proto"],
+  "Request": {
+    "name": "Bob"
+  },
+  "Metadata": {
+    "authorization": "Bearer token"
+  },
+  "Expect": {
+    "Code": "OK",
+    "Response": {
+      "message": "Hello Bob"
+    }
+  }
+}`
+
+func (s *service) registerRoutes() {
+	s.Register(&endly.Route{
+		Action: "call",
+		RequestInfo: &endly.ActionInfo{
+			Description: "call gRPC method",
+			Examples: []*endly.UseCase{
+				{
+					Description: "unary call",
+					Data:        grpcCallExample,
+				},
+			},
+		},
+		RequestProvider: func() interface{} {
+			return &CallRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &CallResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*CallRequest); ok {
+				return s.call(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+}
+
+// New creates a new gRPC runner service
+func New() endly.Service {
+	var result = &service{
+		AbstractService: endly.NewAbstractService(ServiceID),
+	}
+	result.AbstractService.Service = result
+	result.registerRoutes()
+	return result
+}
@@ -0,0 +1,93 @@
+package grpc_test
+
+import (
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	endpoint "github.com/viant/endly/service/testing/endpoint/grpc"
+	runner "github.com/viant/endly/service/testing/runner/grpc"
+	"github.com/viant/toolbox"
+)
+
+func TestGRPCRunnerService_Call(t *testing.T) {
+	manager := endly.New()
+	context := manager.NewContext(toolbox.NewContext())
+	protoFile := path.Join(toolbox.CallerDirectory(3), "test", "greeter.proto")
+
+	response, err := manager.Run(context, &endpoint.ListenRequest{
+		Port:       7731,
+		ProtoFiles: []string{protoFile},
+		Reflection: true,
+		Rules: []*endpoint.Rule{
+			{Method: "greeter.Greeter/SayHello", Request: map[string]interface{}{"name": "missing"}, Code: "NOT_FOUND", Message: "missing"},
+			{Method: "greeter.Greeter/SayHello", Response: map[string]interface{}{"message": "Hello ${request.name}"}, Trailer: map[string]string{"x-mock": "true"}},
+			{Method: "greeter.Greeter/StreamHello", Responses: []interface{}{`{"message":"1"}`, `{"message":"2"}`}},
+		},
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+	defer manager.Run(context, &endpoint.ShutdownRequest{Port: 7731})
+
+	useCases := []struct {
+		description string
+		request     *runner.CallRequest
+		code        string
+		expect      map[string]interface{}
+	}{
+		{
+			description: "unary call with proto file",
+			request: &runner.CallRequest{
+				Target:     "127.0.0.1:7731",
+				Method:     "greeter.Greeter/SayHello",
+				ProtoFiles: []string{protoFile},
+				Request:    map[string]interface{}{"name": "Bob"},
+				Expect:     map[string]interface{}{"Code": "OK", "Response": map[string]interface{}{"message": "Hello Bob"}, "Trailer": map[string]interface{}{"x-mock": "true"}},
+			},
+			code: "OK",
+		},
+		{
+			description: "unary call with reflection",
+			request: &runner.CallRequest{
+				Target:  "127.0.0.1:7731",
+				Method:  "/greeter.Greeter/SayHello",
+				Request: `{"name":"missing"}`,
+			},
+			code: "NotFound",
+		},
+		{
+			description: "server streaming call",
+			request: &runner.CallRequest{
+				Target:  "127.0.0.1:7731",
+				Method:  "greeter.Greeter/StreamHello",
+				Request: map[string]interface{}{"name": "Ann"},
+				Expect:  map[string]interface{}{"Responses": []interface{}{map[string]interface{}{"message": "1"}, map[string]interface{}{"message": "2"}}},
+			},
+			code: "OK",
+		},
+	}
+	for _, useCase := range useCases {
+		response, err = manager.Run(context, useCase.request)
+		if !assert.Nil(t, err, useCase.description) {
+			continue
+		}
+		callResponse := response.(*runner.CallResponse)
+		assert.Equal(t, useCase.code, callResponse.Code, useCase.description)
+		if callResponse.Assert != nil {
+			assert.Equal(t, 0, callResponse.Assert.FailedCount, callResponse.Assert.Report())
+		}
+	}
+
+	response, err = manager.Run(context, &runner.CallRequest{
+		Target:   "127.0.0.1:7731",
+		Method:   "greeter.Greeter/SayHello",
+		Request:  map[string]interface{}{"name": "Tom"},
+		Repeater: &model.Repeater{Variables: model.Variables{{Name: "greeting", From: "Response.message"}}},
+	})
+	if assert.Nil(t, err) {
+		assert.Equal(t, "Hello Tom", response.(*runner.CallResponse).Data["greeting"])
+	}
+}
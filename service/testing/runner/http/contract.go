@@ -6,6 +6,7 @@ import (
 	"github.com/viant/endly/service/testing/validator"
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/data"
+	"sort"
 )
 
 // SendRequest represents a send http request.
@@ -111,16 +112,64 @@ type LoadRequest struct {
 	ThreadCount int    `description:"defines number of http client sending request concurrently, default 3"`
 	Repeat      int    `description:"defines how many times repeat individual request, default 1"`
 	AssertMod   int    `description:"defines modulo for assertion on repeated request (make sure you have enough memory)"`
-	Message     string `description:"reporting message during stress test, the following is available: $load.[QPS|Count|Elapsed|Timeouts|Errors|Error]"`
+	Message     string `description:"reporting message during stress test, the following is available: $load.[QPS|CurrentQPS|TargetQPS|Count|Elapsed|Timeouts|Errors|Error]"`
+
+	HistogramBucketsMs []float64 `description:"response time histogram bucket upper bounds in ms, default: 1,2,5,10,20,50,100,200,500,1000,2000,5000"`
+	SnapshotIntervalMs int       `description:"QPS snapshot interval in ms, default 1000"`
+
+	Rate       float64      `description:"open model target rate (requests per second), requests are sent on schedule regardless of response time, ThreadCount limits in flight requests (default 100)"`
+	RampUpMs   int          `description:"open model ramp-up duration from 0 to Rate"`
+	RampDownMs int          `description:"open model ramp-down duration from Rate to 0"`
+	DurationMs int          `description:"test duration in ms, with Rate it defines steady stage duration (if empty, time to send Repeat x requests at Rate), otherwise requests are sent in a loop until duration elapses"`
+	Stages     []*LoadStage `description:"open model stages, alternative to Rate, RampUpMs, DurationMs and RampDownMs"`
+
+	Users   int                      `description:"number of concurrent virtual users, each running requests as a session where cookies and extracted data flow between requests, default: number of records or ThreadCount"`
+	Dataset string                   `description:"CSV (with header) or JSON (array or new line delimited objects) location with per session parameters"`
+	Records []map[string]interface{} `description:"inline per session parameters, alternative to Dataset"`
+}
+
+// LoadStage represents open model load stage, rate changes linearly from the previous stage rate to stage Rate
+type LoadStage struct {
+	DurationMs int     `description:"stage duration in ms, zero duration changes rate immediately"`
+	Rate       float64 `description:"target rate (requests per second) at the end of the stage"`
+}
+
+// IsSessionMode returns true if requests are sent by virtual users as a session sequence
+func (r *LoadRequest) IsSessionMode() bool {
+	if r.Users > 0 || r.Dataset != "" || len(r.Records) > 0 {
+		return true
+	}
+	for _, request := range r.Requests {
+		if request.When != "" || (request.Repeater != nil && (len(request.Variables) > 0 || len(request.Extract) > 0)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOpenModel returns true if requests are sent with target rate
+func (r *LoadRequest) IsOpenModel() bool {
+	return r.Rate > 0 || len(r.Stages) > 0
 }
 
 func (r *LoadRequest) Init() error {
+	if r.ThreadCount == 0 && r.IsOpenModel() {
+		r.ThreadCount = 100
+	}
 	if r.ThreadCount == 0 {
 		r.ThreadCount = 3
 	}
+
 	if r.Repeat == 0 {
 		r.Repeat = 1
 	}
+	if len(r.HistogramBucketsMs) == 0 {
+		r.HistogramBucketsMs = append([]float64{}, DefaultHistogramBucketsMs...)
+	}
+	sort.Float64s(r.HistogramBucketsMs)
+	if r.SnapshotIntervalMs == 0 {
+		r.SnapshotIntervalMs = 1000
+	}
 	if len(r.Requests) == 0 {
 		return nil
 	}
@@ -138,7 +187,20 @@ func (r *LoadRequest) Init() error {
 	if r.AssertMod == 0 {
 		r.AssertMod = 1024
 	}
+	if r.Rate > 0 && len(r.Stages) == 0 {
+		steadyMs := r.DurationMs
+		if steadyMs == 0 {
+			steadyMs = int(float64(r.Repeat*len(r.Requests)) * 1000 / r.Rate)
+		}
+		r.Stages = []*LoadStage{{DurationMs: r.RampUpMs, Rate: r.Rate}, {DurationMs: steadyMs, Rate: r.Rate}}
+		if r.RampDownMs > 0 {
+			r.Stages = append(r.Stages, &LoadStage{DurationMs: r.RampDownMs, Rate: 0})
+		}
+	}
 
+	if r.Message == "" && r.IsOpenModel() {
+		r.Message = " $load.Elapsed: Count: $load.Count, QPS: $load.QPS, Rate: $load.CurrentQPS/$load.TargetQPS, Timeouts: $load.Timeouts, Errors: $load.Errors, Error: $load.Error"
+	}
 	if r.Message == "" {
 		r.Message = " $load.Elapsed: Count: $load.Count, QPS: $load.QPS, Timeouts: $load.Timeouts, Errors: $load.Errors, Error: $load.Error"
 	}
@@ -149,21 +211,35 @@ func (r *LoadRequest) Validate() error {
 	if len(r.Requests) == 0 {
 		return fmt.Errorf("requests were empty")
 	}
-	for _, request := range r.Requests {
-		if request.When != "" {
-			return fmt.Errorf("conditional execution is not supported in stress test mode")
-		}
-		if len(request.Variables) > 0 {
-			return fmt.Errorf("scraping variables is not supported in stress test mode")
-		}
-		if len(request.Extract) > 0 {
-			return fmt.Errorf("scraping data is not supported in stress test mode")
+	if r.IsSessionMode() && r.IsOpenModel() {
+		return fmt.Errorf("rate and stages are not supported with virtual user sessions")
+	}
+	if r.Rate < 0 {
+		return fmt.Errorf("invalid rate: %v", r.Rate)
+	}
+	for i, stage := range r.Stages {
+		if stage.DurationMs < 0 || stage.Rate < 0 {
+			return fmt.Errorf("invalid stages[%v]: duration: %vms, rate: %v", i, stage.DurationMs, stage.Rate)
 		}
 	}
-
 	return nil
 }
 
+// expectedResponses returns expected responses indexed by request
+func (r *LoadRequest) expectedResponses() []interface{} {
+	if len(r.Expect) == 0 {
+		return nil
+	}
+	responses, ok := r.Expect["Responses"]
+	if !ok {
+		responses, ok = r.Expect["responses"]
+	}
+	if !ok {
+		return nil
+	}
+	return toolbox.AsSlice(responses)
+}
+
 // LoadRequest represents a stress test response
 type LoadResponse struct {
 	SendResponse
@@ -178,4 +254,8 @@ type LoadResponse struct {
 	MinResponseTimeInMs float64
 	AvgResponseTimeInMs float64
 	MaxResponseTimeInMs float64
+	Percentiles         *LatencyPercentiles
+	Histogram           []*HistogramBucket
+	URLs                []*URLMetrics
+	Snapshots           []*QPSSnapshot
 }
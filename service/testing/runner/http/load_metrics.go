@@ -0,0 +1,219 @@
+package http
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// DefaultHistogramBucketsMs represents default response time histogram bucket upper bounds
+var DefaultHistogramBucketsMs = []float64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000}
+
+// LatencyPercentiles represents response time percentiles in ms
+type LatencyPercentiles struct {
+	P50  float64
+	P90  float64
+	P95  float64
+	P99  float64
+	P999 float64
+}
+
+// HistogramBucket represents response time histogram bucket, ToMs is inclusive upper bound, the last bucket has no upper bound
+type HistogramBucket struct {
+	FromMs  float64
+	ToMs    float64 `json:",omitempty"`
+	Count   int
+	Percent float64
+}
+
+// URLMetrics represents load test metrics for a request URL
+type URLMetrics struct {
+	Method              string
+	URL                 string
+	RequestCount        int
+	ErrorCount          int
+	TimeoutCount        int
+	StatusCodes         map[int]int
+	MinResponseTimeInMs float64
+	AvgResponseTimeInMs float64
+	MaxResponseTimeInMs float64
+	Percentiles         *LatencyPercentiles
+}
+
+// QPSSnapshot represents completed requests rate within a time window
+type QPSSnapshot struct {
+	ElapsedSec float64
+	Count      int
+	QPS        float64
+}
+
+// newLatencyPercentiles returns percentiles for sorted durations using nearest rank method
+func newLatencyPercentiles(sorted []time.Duration) *LatencyPercentiles {
+	if len(sorted) == 0 {
+		return &LatencyPercentiles{}
+	}
+	percentile := func(p float64) float64 {
+		index := int(math.Ceil(p/100*float64(len(sorted))-1e-9)) - 1
+		if index < 0 {
+			index = 0
+		}
+		return asMs(sorted[index])
+	}
+	return &LatencyPercentiles{
+		P50:  percentile(50),
+		P90:  percentile(90),
+		P95:  percentile(95),
+		P99:  percentile(99),
+		P999: percentile(99.9),
+	}
+}
+
+// newHistogram returns response time histogram for supplied bucket upper bounds
+func newHistogram(durations []time.Duration, bucketsMs []float64) []*HistogramBucket {
+	var result = make([]*HistogramBucket, 0, len(bucketsMs)+1)
+	from := 0.0
+	for _, to := range bucketsMs {
+		result = append(result, &HistogramBucket{FromMs: from, ToMs: to})
+		from = to
+	}
+	result = append(result, &HistogramBucket{FromMs: from})
+	for _, duration := range durations {
+		elapsedMs := asMs(duration)
+		index := sort.SearchFloat64s(bucketsMs, elapsedMs)
+		result[index].Count++
+	}
+	for _, bucket := range result {
+		if len(durations) > 0 {
+			bucket.Percent = 100 * float64(bucket.Count) / float64(len(durations))
+		}
+	}
+	return result
+}
+
+// newQPSSnapshots returns completed requests rate per interval
+func newQPSSnapshots(trips []*stressTestTrip, startTime, endTime time.Time, interval time.Duration) []*QPSSnapshot {
+	if interval <= 0 || !endTime.After(startTime) {
+		return nil
+	}
+	windows := int(endTime.Sub(startTime)/interval) + 1
+	var result = make([]*QPSSnapshot, windows)
+	for i := range result {
+		result[i] = &QPSSnapshot{ElapsedSec: float64(time.Duration(i+1)*interval) / float64(time.Second)}
+	}
+	for _, trip := range trips {
+		index := int(trip.responseTime.Sub(startTime) / interval)
+		if index < 0 || index >= windows {
+			continue
+		}
+		result[index].Count++
+	}
+	for i, snapshot := range result {
+		window := interval
+		if i == windows-1 {
+			window = endTime.Sub(startTime) - time.Duration(i)*interval
+			snapshot.ElapsedSec = float64(endTime.Sub(startTime)) / float64(time.Second)
+		}
+		if window > 0 {
+			snapshot.QPS = float64(snapshot.Count) / (float64(window) / float64(time.Second))
+		}
+	}
+	return result
+}
+
+// newURLMetrics returns metrics per request method and URL in the first request order
+func newURLMetrics(trips []*stressTestTrip) []*URLMetrics {
+	var result = make([]*URLMetrics, 0)
+	var index = make(map[string]int)
+	var durations = make([][]time.Duration, 0)
+	for _, trip := range trips {
+		if trip.request == nil {
+			continue
+		}
+		key := trip.request.Method + " " + trip.request.URL.String()
+		i, ok := index[key]
+		if !ok {
+			i = len(result)
+			index[key] = i
+			result = append(result, &URLMetrics{Method: trip.request.Method, URL: trip.request.URL.String(), StatusCodes: make(map[int]int)})
+			durations = append(durations, make([]time.Duration, 0))
+		}
+		metrics := result[i]
+		metrics.RequestCount++
+		if trip.err != nil {
+			metrics.ErrorCount++
+		}
+		if trip.timeout {
+			metrics.TimeoutCount++
+		}
+		if trip.statusCode > 0 {
+			metrics.StatusCodes[trip.statusCode]++
+		}
+		durations[i] = append(durations[i], trip.elapsed)
+	}
+	for i, metrics := range result {
+		sorted := durations[i]
+		sort.Slice(sorted, func(a, b int) bool { return sorted[a] < sorted[b] })
+		var cumulative time.Duration
+		for _, duration := range sorted {
+			cumulative += duration
+		}
+		metrics.MinResponseTimeInMs = asMs(sorted[0])
+		metrics.MaxResponseTimeInMs = asMs(sorted[len(sorted)-1])
+		metrics.AvgResponseTimeInMs = asMs(cumulative) / float64(len(sorted))
+		metrics.Percentiles = newLatencyPercentiles(sorted)
+	}
+	return result
+}
+
+// metrics returns load test metrics used by expect validation
+func (r *LoadResponse) metrics() (map[string]interface{}, error) {
+	metrics := *r
+	metrics.SendResponse = SendResponse{}
+	encoded, err := json.Marshal(metrics)
+	if err != nil {
+		return nil, err
+	}
+	var result = make(map[string]interface{})
+	if err = json.Unmarshal(encoded, &result); err != nil {
+		return nil, err
+	}
+	for _, key := range []string{"Responses", "Data", "Assert"} {
+		delete(result, key)
+	}
+	return formatNumbers(result).(map[string]interface{}), nil
+}
+
+// formatNumbers converts numbers to text, so that both numeric and range (/[min..max]/) expectations can be used
+func formatNumbers(value interface{}) interface{} {
+	switch actual := value.(type) {
+	case float64:
+		return strconv.FormatFloat(actual, 'f', -1, 64)
+	case map[string]interface{}:
+		for key, item := range actual {
+			actual[key] = formatNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range actual {
+			actual[i] = formatNumbers(item)
+		}
+	}
+	return value
+}
+
+// metricsExpect returns expectations other than responses, they are validated with load test metrics
+func (r *LoadRequest) metricsExpect() map[string]interface{} {
+	var result = make(map[string]interface{})
+	for key, value := range r.Expect {
+		if key == "Responses" || key == "responses" {
+			continue
+		}
+		result[key] = value
+	}
+	return result
+}
+
+func asMs(duration time.Duration) float64 {
+	return float64(duration) / float64(time.Millisecond)
+}
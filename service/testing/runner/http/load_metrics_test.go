@@ -0,0 +1,111 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/assertly"
+)
+
+func TestNewLatencyPercentiles(t *testing.T) {
+	var durations = make([]time.Duration, 0, 1000)
+	for i := 1; i <= 1000; i++ {
+		durations = append(durations, time.Duration(i)*time.Millisecond)
+	}
+	percentiles := newLatencyPercentiles(durations)
+	assert.Equal(t, &LatencyPercentiles{P50: 500, P90: 900, P95: 950, P99: 990, P999: 999}, percentiles)
+	assert.Equal(t, &LatencyPercentiles{}, newLatencyPercentiles(nil))
+}
+
+func TestNewHistogram(t *testing.T) {
+	durations := []time.Duration{
+		500 * time.Microsecond,
+		time.Millisecond,
+		3 * time.Millisecond,
+		7 * time.Millisecond,
+		20 * time.Second,
+	}
+	histogram := newHistogram(durations, []float64{1, 5, 10})
+	assert.Equal(t, 4, len(histogram))
+	assert.Equal(t, 2, histogram[0].Count)
+	assert.Equal(t, 40.0, histogram[0].Percent)
+	assert.Equal(t, 1, histogram[1].Count)
+	assert.Equal(t, 1, histogram[2].Count)
+	assert.Equal(t, 10.0, histogram[3].FromMs)
+	assert.Equal(t, 0.0, histogram[3].ToMs)
+	assert.Equal(t, 1, histogram[3].Count)
+}
+
+func TestNewURLMetricsAndSnapshots(t *testing.T) {
+	startTime := time.Now()
+	newTrip := func(URL string, offset, elapsed time.Duration, statusCode int) *stressTestTrip {
+		request, _ := http.NewRequest("GET", URL, nil)
+		return &stressTestTrip{
+			request:      request,
+			requestTime:  startTime.Add(offset),
+			responseTime: startTime.Add(offset + elapsed),
+			elapsed:      elapsed,
+			statusCode:   statusCode,
+		}
+	}
+	trips := []*stressTestTrip{
+		newTrip("http://127.0.0.1/a", 0, 10*time.Millisecond, 200),
+		newTrip("http://127.0.0.1/b", 0, 30*time.Millisecond, 404),
+		newTrip("http://127.0.0.1/a", 500*time.Millisecond, 20*time.Millisecond, 200),
+		newTrip("http://127.0.0.1/a", 1200*time.Millisecond, 300*time.Millisecond, 200),
+	}
+	metrics := newURLMetrics(trips)
+	assert.Equal(t, 2, len(metrics))
+	assert.Equal(t, "http://127.0.0.1/a", metrics[0].URL)
+	assert.Equal(t, 3, metrics[0].RequestCount)
+	assert.Equal(t, map[int]int{200: 3}, metrics[0].StatusCodes)
+	assert.Equal(t, 10.0, metrics[0].MinResponseTimeInMs)
+	assert.Equal(t, 300.0, metrics[0].MaxResponseTimeInMs)
+	assert.Equal(t, 110.0, metrics[0].AvgResponseTimeInMs)
+	assert.Equal(t, 20.0, metrics[0].Percentiles.P50)
+	assert.Equal(t, map[int]int{404: 1}, metrics[1].StatusCodes)
+
+	snapshots := newQPSSnapshots(trips, startTime, startTime.Add(1500*time.Millisecond), time.Second)
+	assert.Equal(t, 2, len(snapshots))
+	assert.Equal(t, 3, snapshots[0].Count)
+	assert.Equal(t, 3.0, snapshots[0].QPS)
+	assert.Equal(t, 1, snapshots[1].Count)
+	assert.Equal(t, 1.5, snapshots[1].ElapsedSec)
+	assert.Equal(t, 2.0, snapshots[1].QPS)
+}
+
+func TestLoadResponse_Metrics(t *testing.T) {
+	response := &LoadResponse{
+		QPS:         1200,
+		Percentiles: &LatencyPercentiles{P99: 150},
+		StatusCodes: map[int]int{200: 10},
+	}
+	actual, err := response.metrics()
+	assert.Nil(t, err)
+	_, hasResponses := actual["Responses"]
+	assert.False(t, hasResponses)
+
+	request := &LoadRequest{SendRequest: &SendRequest{Expect: map[string]interface{}{
+		"Responses": []interface{}{},
+		"QPS":       "/[1000..1000000]/",
+		"Percentiles": map[string]interface{}{
+			"P99": "/[0..200]/",
+		},
+		"StatusCodes": map[string]interface{}{
+			"200": 10,
+		},
+	}}}
+	expect := request.metricsExpect()
+	assert.Equal(t, 3, len(expect))
+	validation, err := assertly.Assert(expect, actual, assertly.NewDataPath("/"))
+	assert.Nil(t, err)
+	assert.Equal(t, 0, validation.FailedCount, validation.Report())
+
+	response.Percentiles.P99 = 250
+	actual, _ = response.metrics()
+	validation, err = assertly.Assert(expect, actual, assertly.NewDataPath("/"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, validation.FailedCount)
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/viant/endly"
+)
+
+// loadSchedule represents open model target rate schedule
+type loadSchedule struct {
+	stages []*LoadStage
+}
+
+// rate returns target rate at elapsed time, false if schedule has ended
+func (s *loadSchedule) rate(elapsed time.Duration) (float64, bool) {
+	previous := 0.0
+	var offset time.Duration
+	for _, stage := range s.stages {
+		duration := time.Duration(stage.DurationMs) * time.Millisecond
+		if elapsed < offset+duration {
+			progress := float64(elapsed-offset) / float64(duration)
+			return previous + (stage.Rate-previous)*progress, true
+		}
+		offset += duration
+		previous = stage.Rate
+	}
+	return previous, false
+}
+
+// at returns elapsed time when cumulative expected request count (integral of the rate) reaches supplied count,
+// false if schedule ends before
+func (s *loadSchedule) at(count float64) (time.Duration, bool) {
+	previous := 0.0
+	var offset time.Duration
+	for _, stage := range s.stages {
+		duration := time.Duration(stage.DurationMs) * time.Millisecond
+		if count <= 0 {
+			return offset, true
+		}
+		seconds := duration.Seconds()
+		expected := (previous + stage.Rate) / 2 * seconds
+		if count <= expected {
+			//solves previous*t + (stage.Rate-previous)/(2*seconds)*t^2 = count
+			a := (stage.Rate - previous) / (2 * seconds)
+			t := 2 * count / (previous + math.Sqrt(previous*previous+4*a*count))
+			return offset + time.Duration(math.Min(t, seconds)*float64(time.Second)), true
+		}
+		count -= expected
+		offset += duration
+		previous = stage.Rate
+	}
+	return offset, false
+}
+
+// generateTrips sends requests in a loop, on target rate schedule for open model, or until duration elapses otherwise
+func (s *service) generateTrips(context *endly.Context, request *LoadRequest, channel chan *stressTestTrip, waitGroup *sync.WaitGroup, metric *runtimeMetric) ([]*stressTestTrip, error) {
+	var sessionCookies = []*http.Cookie{}
+	var trips = make([]*stressTestTrip, 0)
+	expectedResponses := request.expectedResponses()
+	state := context.State()
+	for _, req := range request.Requests {
+		req.Expand(state)
+	}
+	defer metric.setTargetRate(0)
+	schedule := &loadSchedule{stages: request.Stages}
+	duration := time.Duration(request.DurationMs) * time.Millisecond
+	started := time.Now()
+	for count := 0; ; {
+		var scheduled time.Time
+		if request.IsOpenModel() {
+			elapsed, ok := schedule.at(float64(count))
+			if !ok {
+				break
+			}
+			rate, _ := schedule.rate(elapsed)
+			metric.setTargetRate(rate)
+			scheduled = started.Add(elapsed)
+			if wait := time.Until(scheduled); wait > 0 {
+				time.Sleep(wait)
+			}
+		} else if time.Since(started) >= duration {
+			break
+		}
+		index := count % len(request.Requests)
+		round := count / len(request.Requests)
+		trip := &stressTestTrip{
+			waitGroup: waitGroup,
+			index:     index,
+		}
+		if round%request.AssertMod == 0 && index < len(expectedResponses) { //add validation to the first response from repeated
+			trip.expected = true
+		}
+		trip.scheduledTime = scheduled
+		var err error
+		if trip.request, trip.expectBinary, err = request.Requests[index].Build(context, sessionCookies); err != nil {
+			return nil, err
+		}
+		trips = append(trips, trip)
+		waitGroup.Add(1)
+		channel <- trip
+		count++
+	}
+	return trips, nil
+}
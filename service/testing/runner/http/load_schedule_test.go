@@ -0,0 +1,109 @@
+package http
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadSchedule_Rate(t *testing.T) {
+	request := &LoadRequest{
+		SendRequest: &SendRequest{Requests: []*Request{{Method: "GET", URL: "http://127.0.0.1/"}}},
+		Rate:        100,
+		RampUpMs:    1000,
+		DurationMs:  2000,
+		RampDownMs:  1000,
+	}
+	assert.Nil(t, request.Init())
+	assert.True(t, request.IsOpenModel())
+	assert.Equal(t, 100, request.ThreadCount)
+	assert.Equal(t, 3, len(request.Stages))
+	schedule := &loadSchedule{stages: request.Stages}
+
+	var useCases = []struct {
+		elapsed time.Duration
+		rate    float64
+		ok      bool
+	}{
+		{0, 0, true},
+		{500 * time.Millisecond, 50, true},
+		{time.Second, 100, true},
+		{2 * time.Second, 100, true},
+		{3500 * time.Millisecond, 50, true},
+		{4 * time.Second, 0, false},
+	}
+	for _, useCase := range useCases {
+		rate, ok := schedule.rate(useCase.elapsed)
+		assert.Equal(t, useCase.ok, ok, useCase.elapsed)
+		assert.InDelta(t, useCase.rate, rate, 0.001, useCase.elapsed)
+	}
+}
+
+func TestLoadRequest_Init_SteadyRate(t *testing.T) {
+	request := &LoadRequest{
+		SendRequest: &SendRequest{Requests: []*Request{{Method: "GET", URL: "http://127.0.0.1/a"}, {Method: "GET", URL: "http://127.0.0.1/b"}}},
+		Rate:        50,
+		Repeat:      100,
+	}
+	assert.Nil(t, request.Init())
+	assert.Equal(t, []*LoadStage{{DurationMs: 0, Rate: 50}, {DurationMs: 4000, Rate: 50}}, request.Stages)
+	schedule := &loadSchedule{stages: request.Stages}
+	rate, ok := schedule.rate(0)
+	assert.True(t, ok)
+	assert.Equal(t, 50.0, rate)
+}
+
+func TestLoadSchedule_At(t *testing.T) {
+	//ramp up to 100, ramp down to 0, idle, ramp up to 100 again
+	schedule := &loadSchedule{stages: []*LoadStage{{DurationMs: 1000, Rate: 100}, {DurationMs: 1000, Rate: 0}, {DurationMs: 1000, Rate: 0}, {DurationMs: 1000, Rate: 100}}}
+	var useCases = []struct {
+		count   float64
+		elapsed time.Duration
+		ok      bool
+	}{
+		{0, 0, true},
+		{2, 200 * time.Millisecond, true},
+		{50, time.Second, true},
+		{100, 2 * time.Second, true},
+		{101, 3141 * time.Millisecond, true},
+		{150, 4 * time.Second, true},
+		{151, 0, false},
+	}
+	for _, useCase := range useCases {
+		elapsed, ok := schedule.at(useCase.count)
+		assert.Equal(t, useCase.ok, ok, useCase.count)
+		if useCase.ok {
+			assert.InDelta(t, float64(useCase.elapsed), float64(elapsed), float64(time.Millisecond), useCase.count)
+		}
+	}
+}
+
+func TestService_GenerateTrips(t *testing.T) {
+	request := &LoadRequest{
+		SendRequest: &SendRequest{Requests: []*Request{{Method: "GET", URL: "http://127.0.0.1/"}}},
+		Rate:        100,
+		RampUpMs:    200,
+		DurationMs:  100,
+	}
+	assert.Nil(t, request.Init())
+	channel := make(chan *stressTestTrip, 100)
+	started := time.Now()
+	trips, err := newServiceForTest().generateTrips(newContextWithState(nil), request, channel, &sync.WaitGroup{}, &runtimeMetric{})
+	if !assert.Nil(t, err) {
+		return
+	}
+	elapsed := time.Since(started)
+	assert.Equal(t, 21, len(trips))
+	assert.True(t, elapsed >= 280*time.Millisecond && elapsed < 400*time.Millisecond, elapsed)
+	first := trips[0].scheduledTime
+	for k, trip := range trips {
+		expect := time.Duration(math.Sqrt(float64(k)*0.004) * float64(time.Second)) //ramp: 100 * t^2 / (2 * 0.2s) = k
+		if k >= 10 {
+			expect = 200*time.Millisecond + time.Duration(k-10)*10*time.Millisecond
+		}
+		assert.InDelta(t, float64(expect), float64(trip.scheduledTime.Sub(first)), float64(time.Millisecond), k)
+	}
+}
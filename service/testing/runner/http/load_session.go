@@ -0,0 +1,245 @@
+package http
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/criteria"
+	"github.com/viant/endly/model/criteria/eval"
+	"github.com/viant/endly/model/location"
+	"github.com/viant/toolbox"
+)
+
+// LoadSessionKey represents state key with virtual user session info: User and Session number
+const LoadSessionKey = "loadSession"
+
+// loadSession represents virtual user session
+type loadSession struct {
+	user      int
+	context   *endly.Context
+	client    *http.Client
+	extracted map[string]interface{}
+}
+
+// runSessions runs requests sequence by virtual users, Repeat times per user, or until duration elapses
+func (s *service) runSessions(context *endly.Context, request *LoadRequest, metric *runtimeMetric) ([]*stressTestTrip, error) {
+	records, err := s.loadRecords(context, request)
+	if err != nil {
+		return nil, err
+	}
+	users := request.Users
+	if users == 0 {
+		users = len(records)
+	}
+	if users == 0 {
+		users = request.ThreadCount
+	}
+	for _, req := range request.Requests { //build converts JSON body, do it before running concurrently
+		if req.Body == "" && req.JSONBody != nil {
+			if req.Body, err = toolbox.AsJSONText(req.JSONBody); err != nil {
+				return nil, err
+			}
+		}
+	}
+	var sessions = make([]*loadSession, 0, users)
+	for i := 0; i < users; i++ {
+		client, err := toolbox.NewHttpClient(s.applyDefaultTimeoutIfNeeded(context, request.httpOptions)...)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &loadSession{user: i, context: context.Clone(), client: client})
+	}
+
+	var trips = make([]*stressTestTrip, 0)
+	var mutex = &sync.Mutex{}
+	var waitGroup = &sync.WaitGroup{}
+	var counter uint32
+	var sessionErr error
+	duration := time.Duration(request.DurationMs) * time.Millisecond
+	started := time.Now()
+	for _, session := range sessions {
+		waitGroup.Add(1)
+		go func(session *loadSession) {
+			defer waitGroup.Done()
+			for i := 0; ; i++ {
+				if duration > 0 && time.Since(started) >= duration {
+					return
+				}
+				if duration == 0 && i >= request.Repeat {
+					return
+				}
+				number := int(atomic.AddUint32(&counter, 1) - 1)
+				var record map[string]interface{}
+				if len(records) > 0 {
+					record = records[number%len(records)]
+				}
+				sessionTrips, err := s.runSession(session, request, record, number, metric)
+				mutex.Lock()
+				trips = append(trips, sessionTrips...)
+				if err != nil && sessionErr == nil {
+					sessionErr = err
+				}
+				mutex.Unlock()
+				if err != nil {
+					return
+				}
+			}
+		}(session)
+	}
+	waitGroup.Wait()
+	return trips, sessionErr
+}
+
+// runSession sends requests sequence, session ends when request fails or exit criteria is met
+func (s *service) runSession(session *loadSession, request *LoadRequest, record map[string]interface{}, number int, metric *runtimeMetric) ([]*stressTestTrip, error) {
+	context := session.context
+	state := context.State()
+	for key := range session.extracted {
+		state.Delete(key)
+	}
+	session.extracted = make(map[string]interface{})
+	for key, value := range record {
+		state.Put(key, value)
+	}
+	state.Put(LoadSessionKey, map[string]interface{}{
+		"User":    session.user,
+		"Session": number,
+	})
+	expectedResponses := request.expectedResponses()
+	var cookies Cookies = make([]*http.Cookie, 0)
+	var trips = make([]*stressTestTrip, 0, len(request.Requests))
+	for index, req := range request.Requests {
+		var whenEval eval.Compute
+		canRun, err := criteria.Evaluate(nil, state, req.When, &whenEval, "HttpRequest.When", true)
+		if err != nil {
+			return trips, err
+		}
+		if !canRun {
+			continue
+		}
+		trip := &stressTestTrip{
+			waitGroup: &sync.WaitGroup{},
+			index:     index,
+			session:   true,
+			expected:  number%request.AssertMod == 0 && index < len(expectedResponses),
+		}
+		if trip.request, trip.expectBinary, err = req.Build(context, cookies); err != nil {
+			return trips, err
+		}
+		trip.waitGroup.Add(1)
+		s.handleRequest(session.client, metric, trip)
+		trips = append(trips, trip)
+		if trip.response == nil {
+			return trips, nil
+		}
+		cookies.AddCookies(trip.response.Cookies()...)
+		canContinue, err := s.extractSessionData(context, req, trip, session.extracted)
+		if !trip.expected {
+			trip.response = nil
+		}
+		if err != nil {
+			trip.err = err
+			metric.addError(err)
+			return trips, nil
+		}
+		if !canContinue {
+			return trips, nil
+		}
+	}
+	return trips, nil
+}
+
+// extractSessionData applies request variables, extract and exit criteria to trip response, extracted data is published to session state
+func (s *service) extractSessionData(context *endly.Context, request *Request, trip *stressTestTrip, extracted map[string]interface{}) (bool, error) {
+	if request.Repeater == nil || (len(request.Variables) == 0 && len(request.Extract) == 0 && request.Exit == "") {
+		return true, nil
+	}
+	content, err := ioutil.ReadAll(trip.response.Body)
+	if err != nil {
+		return false, err
+	}
+	trip.response.Body = ioutil.NopCloser(bytes.NewReader(content))
+	httpResponse := *trip.response
+	httpResponse.Body = ioutil.NopCloser(bytes.NewReader(content))
+	response := NewResponse()
+	response.Merge(&httpResponse, trip.expectBinary)
+	if err = response.TransformBodyIfNeeded(context, request); err != nil {
+		return false, err
+	}
+	var out interface{} = response.Body
+	if request.DataSource == "response" {
+		out = toolbox.AsMap(response)
+	}
+	var data = make(map[string]interface{})
+	canContinue, err := request.Repeater.Eval(context, RunnerID, out, data)
+	state := context.State()
+	for key, value := range data {
+		extracted[key] = value
+		state.Put(key, value)
+	}
+	return canContinue, err
+}
+
+// loadRecords returns per session parameters from inline records or dataset
+func (s *service) loadRecords(context *endly.Context, request *LoadRequest) ([]map[string]interface{}, error) {
+	if request.Dataset == "" {
+		return request.Records, nil
+	}
+	resource := location.NewResource(context.Expand(request.Dataset))
+	text, err := resource.DownloadText()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dataset %v, %w", resource.URL, err)
+	}
+	records, err := decodeRecords(resource.URL, text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode dataset %v, %w", resource.URL, err)
+	}
+	return append(records, request.Records...), nil
+}
+
+// decodeRecords decodes CSV with header, JSON array or new line delimited JSON records
+func decodeRecords(URL, text string) ([]map[string]interface{}, error) {
+	var result = make([]map[string]interface{}, 0)
+	text = strings.TrimSpace(text)
+	if strings.HasSuffix(strings.ToLower(URL), ".csv") {
+		rows, err := csv.NewReader(strings.NewReader(text)).ReadAll()
+		if err != nil || len(rows) == 0 {
+			return result, err
+		}
+		header := rows[0]
+		for _, row := range rows[1:] {
+			var record = make(map[string]interface{})
+			for i, value := range row {
+				if i < len(header) {
+					record[strings.TrimSpace(header[i])] = value
+				}
+			}
+			result = append(result, record)
+		}
+		return result, nil
+	}
+	if strings.HasPrefix(text, "[") {
+		err := json.Unmarshal([]byte(text), &result)
+		return result, err
+	}
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		var record = make(map[string]interface{})
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			return nil, err
+		}
+		result = append(result, record)
+	}
+	return result, nil
+}
@@ -0,0 +1,92 @@
+package http
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly/model"
+)
+
+func TestDecodeRecords(t *testing.T) {
+	var useCases = []struct {
+		description string
+		URL         string
+		text        string
+		expect      []map[string]interface{}
+	}{
+		{
+			description: "csv with header",
+			URL:         "users.csv",
+			text:        "user,password\nbob,p1\nalice,p2\n",
+			expect: []map[string]interface{}{
+				{"user": "bob", "password": "p1"},
+				{"user": "alice", "password": "p2"},
+			},
+		},
+		{
+			description: "json array",
+			URL:         "users.json",
+			text:        `[{"user":"bob","id":1}]`,
+			expect: []map[string]interface{}{
+				{"user": "bob", "id": 1.0},
+			},
+		},
+		{
+			description: "new line delimited json",
+			URL:         "users.json",
+			text:        "{\"user\":\"bob\"}\n\n{\"user\":\"alice\"}\n",
+			expect: []map[string]interface{}{
+				{"user": "bob"},
+				{"user": "alice"},
+			},
+		},
+	}
+	for _, useCase := range useCases {
+		actual, err := decodeRecords(useCase.URL, useCase.text)
+		assert.Nil(t, err, useCase.description)
+		assert.Equal(t, useCase.expect, actual, useCase.description)
+	}
+}
+
+func TestLoadRequest_IsSessionMode(t *testing.T) {
+	newRequest := func(request *Request) *LoadRequest {
+		return &LoadRequest{SendRequest: &SendRequest{Requests: []*Request{request}}}
+	}
+	request := newRequest(&Request{Method: "GET", URL: "http://127.0.0.1/"})
+	assert.Nil(t, request.Init())
+	assert.False(t, request.IsSessionMode())
+
+	request = newRequest(&Request{Method: "GET", URL: "http://127.0.0.1/", When: "$token:/.+/"})
+	assert.Nil(t, request.Init())
+	assert.True(t, request.IsSessionMode())
+	assert.Nil(t, request.Validate())
+
+	request = newRequest(&Request{Method: "GET", URL: "http://127.0.0.1/", Repeater: &model.Repeater{Variables: model.Variables{{Name: "token", From: "token"}}}})
+	assert.Nil(t, request.Init())
+	assert.True(t, request.IsSessionMode())
+
+	request = newRequest(&Request{Method: "GET", URL: "http://127.0.0.1/"})
+	request.Records = []map[string]interface{}{{"user": "bob"}}
+	request.Rate = 10
+	assert.Nil(t, request.Init())
+	assert.True(t, request.IsSessionMode())
+	assert.NotNil(t, request.Validate())
+}
+
+func TestRuntimeMetric_AddError(t *testing.T) {
+	metric := &runtimeMetric{}
+	var waitGroup sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		waitGroup.Add(1)
+		go func(i int) {
+			defer waitGroup.Done()
+			metric.addError(fmt.Errorf("user %v failed", i))
+			_ = metric.lastError()
+		}(i)
+	}
+	waitGroup.Wait()
+	assert.EqualValues(t, 8, metric.errors)
+	assert.NotNil(t, metric.lastError())
+}
@@ -98,7 +98,7 @@ func (r *Request) Build(context *endly.Context, sessionCookies Cookies) (*http.R
 
 	copyHeaders(request.Header, httpRequest.Header)
 	//Set cookies from active session
-	SetCookies(sessionCookies, request.Header)
+	SetCookies(sessionCookies, httpRequest.Header)
 	//Set cookies from user http request
 	SetCookies(request.Cookies, httpRequest.Header)
 	return httpRequest, expectBinary, nil
@@ -14,8 +14,10 @@ import (
 	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/data"
 	"io/ioutil"
+	"math"
 	"net"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -96,6 +98,7 @@ func (s *service) sendRequest(context *endly.Context, client *http.Client, reque
 	var response *Response
 	bodyProvider, err := getRequestBodyReader(httpRequest, repeater.Repeat)
 
+	httpRequest = httpRequest.WithContext(context.Background()) //honors workflow node deadline
 	handler := func() (interface{}, error) {
 		httpRequest.Body = bodyProvider()
 		httpResponse, err := client.Do(httpRequest)
@@ -214,6 +217,9 @@ func (s *service) handleRequest(client *http.Client, metric *runtimeMetric, trip
 	response, err = client.Do(trip.request)
 	trip.responseTime = time.Now()
 	trip.elapsed = trip.responseTime.Sub(trip.requestTime)
+	if !trip.scheduledTime.IsZero() { //open model latency includes waiting for available client
+		trip.elapsed = trip.responseTime.Sub(trip.scheduledTime)
+	}
 	atomic.AddUint32(&metric.count, 1)
 
 	if err, ok := err.(net.Error); ok && err.Timeout() {
@@ -222,8 +228,7 @@ func (s *service) handleRequest(client *http.Client, metric *runtimeMetric, trip
 	} else if err != nil {
 		fmt.Printf("%v\n", err)
 		trip.err = err
-		metric.err = err
-		atomic.AddUint32(&metric.errors, 1)
+		metric.addError(err)
 		return
 	}
 	defer func() {
@@ -235,12 +240,13 @@ func (s *service) handleRequest(client *http.Client, metric *runtimeMetric, trip
 	if trip.err != nil || trip.timeout || response == nil {
 		return
 	}
+	trip.statusCode = response.StatusCode
 	var content []byte
-	if response.ContentLength > 0 {
+	if response.ContentLength != 0 {
 		content, err = ioutil.ReadAll(response.Body)
 	}
 
-	if trip.expected {
+	if trip.expected || trip.session {
 		trip.response = &http.Response{
 			Header:        response.Header,
 			Status:        response.Status,
@@ -260,7 +266,10 @@ func (s *service) handleRequests(client *http.Client, sendChannel chan *stressTe
 		case trip := <-sendChannel:
 			s.handleRequest(client, metric, trip)
 		case <-time.After(15 * time.Second):
-			return
+			if atomic.LoadUint32(done) == 1 {
+				return
+			}
+			continue
 		}
 		if atomic.LoadUint32(done) == 1 {
 			return
@@ -269,27 +278,63 @@ func (s *service) handleRequests(client *http.Client, sendChannel chan *stressTe
 }
 
 type runtimeMetric struct {
-	count     uint32
-	startTime int64
-	timeouts  uint32
-	errors    uint32
-	err       error
+	count      uint32
+	startTime  int64
+	timeouts   uint32
+	errors     uint32
+	targetRate uint64
+	mux        sync.Mutex
+	err        error
+}
+
+// addError counts error and keeps it as the last error, it is called from concurrent clients and virtual users
+func (m *runtimeMetric) addError(err error) {
+	atomic.AddUint32(&m.errors, 1)
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	m.err = err
+}
+
+// lastError returns the last error
+func (m *runtimeMetric) lastError() error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+	return m.err
+}
+
+func (m *runtimeMetric) setTargetRate(rate float64) {
+	atomic.StoreUint64(&m.targetRate, math.Float64bits(rate))
+}
+
+func (m *runtimeMetric) getTargetRate() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&m.targetRate))
 }
 
 func (s *service) stressTest(context *endly.Context, request *LoadRequest) (*LoadResponse, error) {
 	var waitGroup = &sync.WaitGroup{}
 	capacity := 1024 * request.ThreadCount
+	if request.DurationMs > 0 && !request.IsOpenModel() {
+		capacity = request.ThreadCount
+	}
 	var sendChannel = make(chan *stressTestTrip, capacity)
 	var done uint32 = 0
 	metrics := &runtimeMetric{}
 
 	go s.emitMetrics(context, metrics, &done, request.Message)
-	if _, err := s.initClients(context, request, sendChannel, metrics, &done); err != nil {
-		return nil, err
+	var trips []*stressTestTrip
+	var err error
+	if request.IsSessionMode() {
+		trips, err = s.runSessions(context, request, metrics)
+	} else if _, err = s.initClients(context, request, sendChannel, metrics, &done); err == nil {
+		if request.IsOpenModel() || request.DurationMs > 0 {
+			trips, err = s.generateTrips(context, request, sendChannel, waitGroup, metrics)
+		} else {
+			partialTrips := newPartialStressTrips(capacity, sendChannel, waitGroup)
+			trips, err = buildStressTestTrip(request, context, partialTrips)
+		}
 	}
-	partialTrips := newPartialStressTrips(capacity, sendChannel, waitGroup)
-	trips, err := buildStressTestTrip(request, context, partialTrips)
 	if err != nil {
+		atomic.StoreUint32(&done, 1)
 		return nil, err
 	}
 	waitGroup.Wait()
@@ -305,8 +350,8 @@ func (s *service) stressTest(context *endly.Context, request *LoadRequest) (*Loa
 	response.Assert = &validator.AssertResponse{Validation: &assertly.Validation{}}
 	var actual = make([]interface{}, 0)
 	var expected = make([]interface{}, 0)
-
-	if request.Expect != nil {
+	metricsExpect := request.metricsExpect()
+	if request.Expect != nil && (len(request.expectedResponses()) > 0 || len(metricsExpect) == 0) {
 		for _, trip := range trips {
 			if !trip.expected {
 				continue
@@ -336,11 +381,28 @@ func (s *service) stressTest(context *endly.Context, request *LoadRequest) (*Loa
 
 		}
 		response.Assert, err = validator.Assert(context, request, expected, actual, "HTTP.Responses", "assert http responses")
+		if err != nil {
+			return response, err
+		}
 	}
-	return response, err
+	if len(metricsExpect) > 0 {
+		actualMetrics, err := response.metrics()
+		if err != nil {
+			return nil, err
+		}
+		metricsAssert, err := validator.Assert(context, request, metricsExpect, actualMetrics, "HTTP.Load", "assert load test metrics")
+		if err != nil {
+			return response, err
+		}
+		response.Assert.Validation.MergeFrom(metricsAssert.Validation)
+	}
+	return response, nil
 }
 
 func collectTripResponses(trips []*stressTestTrip, response *LoadResponse, request *LoadRequest) error {
+	if len(trips) == 0 {
+		return fmt.Errorf("no requests were sent")
+	}
 	startTime := trips[0].requestTime
 	endTime := trips[0].responseTime
 	minResponse := trips[0].elapsed
@@ -348,8 +410,10 @@ func collectTripResponses(trips []*stressTestTrip, response *LoadResponse, reque
 
 	response.StatusCodes = make(map[int]int)
 	var cumulativeResponse time.Duration
+	var durations = make([]time.Duration, 0, len(trips))
 	//collect responses and build validation collection
 	for _, trip := range trips {
+		durations = append(durations, trip.elapsed)
 		if trip.err != nil {
 			response.ErrorCount++
 			response.Error = trip.err.Error()
@@ -383,21 +447,30 @@ func collectTripResponses(trips []*stressTestTrip, response *LoadResponse, reque
 	response.TestDurationSec = float64(testDuration) / float64(time.Second)
 	response.RequestCount = len(trips)
 	response.QPS = float64(len(trips)) / response.TestDurationSec
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	response.Percentiles = newLatencyPercentiles(durations)
+	response.Histogram = newHistogram(durations, request.HistogramBucketsMs)
+	response.URLs = newURLMetrics(trips)
+	response.Snapshots = newQPSSnapshots(trips, startTime, endTime, time.Duration(request.SnapshotIntervalMs)*time.Millisecond)
 	return nil
 }
 
 type stressTestTrip struct {
-	index        int
-	err          error
-	timeout      bool
-	expectBinary bool
-	request      *http.Request
-	response     *http.Response
-	expected     bool
-	waitGroup    *sync.WaitGroup
-	requestTime  time.Time
-	responseTime time.Time
-	elapsed      time.Duration
+	index         int
+	err           error
+	timeout       bool
+	expectBinary  bool
+	request       *http.Request
+	response      *http.Response
+	expected      bool
+	waitGroup     *sync.WaitGroup
+	requestTime   time.Time
+	responseTime  time.Time
+	elapsed       time.Duration
+	statusCode    int
+	scheduledTime time.Time
+	session       bool
 }
 
 func buildStressTestTrip(request *LoadRequest, context *endly.Context, partials *partialStressTrips) ([]*stressTestTrip, error) {
@@ -405,16 +478,7 @@ func buildStressTestTrip(request *LoadRequest, context *endly.Context, partials
 	var err error
 	var trips = make([]*stressTestTrip, 0)
 
-	var expectedResponses []interface{}
-	if len(request.Expect) > 0 {
-		responses, ok := request.Expect["Responses"]
-		if !ok {
-			responses, ok = request.Expect["responses"]
-		}
-		if ok {
-			expectedResponses = toolbox.AsSlice(responses)
-		}
-	}
+	expectedResponses := request.expectedResponses()
 
 	for index, req := range request.Requests {
 		var state = context.State()
@@ -562,28 +626,38 @@ func (s *service) emitMetrics(context *endly.Context, metric *runtimeMetric, don
 	}
 	state := context.State()
 	private := state.Clone()
+	var previousCount uint32
+	var previousTime time.Time
 	for atomic.LoadUint32(done) == 0 {
 		count := atomic.LoadUint32(&metric.count)
 		if count == 0 {
 			time.Sleep(time.Second)
 			continue
 		}
-		timeTakenNs := time.Now().UnixNano() - atomic.LoadInt64(&metric.startTime)
+		now := time.Now()
+		timeTakenNs := now.UnixNano() - atomic.LoadInt64(&metric.startTime)
 		timeTakenSec := float64(timeTakenNs) / float64(time.Second)
 		qps := 0.0
 		if timeTakenSec > 0 {
 			qps = float64(count) / timeTakenSec
 		}
+		currentQPS := qps
+		if !previousTime.IsZero() {
+			currentQPS = float64(count-previousCount) / now.Sub(previousTime).Seconds()
+		}
+		previousCount, previousTime = count, now
 
 		loadInfo := data.NewMap()
 		loadInfo.Put("QPS", fmt.Sprintf("%9v", float64(int(qps*10.0))/10.0))
+		loadInfo.Put("CurrentQPS", fmt.Sprintf("%9v", float64(int(currentQPS*10.0))/10.0))
+		loadInfo.Put("TargetQPS", fmt.Sprintf("%9v", float64(int(metric.getTargetRate()*10.0))/10.0))
 		loadInfo.Put("Count", fmt.Sprintf("%9v", count))
 		loadInfo.Put("Elapsed", fmt.Sprintf("%9v", fmt.Sprintf("%s", (time.Duration(timeTakenSec)*time.Second))))
 		loadInfo.Put("Errors", fmt.Sprintf("%4v", atomic.LoadUint32(&metric.errors)))
 		loadInfo.Put("Timeouts", fmt.Sprintf("%4v", atomic.LoadUint32(&metric.timeouts)))
 		errMessage := ""
-		if metric.err != nil {
-			errMessage = metric.err.Error()
+		if err := metric.lastError(); err != nil {
+			errMessage = err.Error()
 		}
 		loadInfo.Put("Error", errMessage)
 		private.Put("load", loadInfo)
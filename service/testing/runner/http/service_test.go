@@ -2,8 +2,10 @@ package http
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/viant/endly"
@@ -180,3 +182,55 @@ func TestTuneDefaultTransport_IdempotentAndRaceFree(t *testing.T) {
 		assert.Equal(t, 100, transport.MaxIdleConnsPerHost)
 	}
 }
+
+// TestService_SendHonorsDeadline ensures in flight request is cancelled once workflow node deadline is exceeded
+func TestService_SendHonorsDeadline(t *testing.T) {
+	release := make(chan bool)
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	s := newServiceForTest()
+	ctx := newContextWithState(nil)
+	restore := ctx.WithTimeout("action send", 50*time.Millisecond)
+	defer restore()
+	request := &SendRequest{Requests: []*Request{{Method: "GET", URL: server.URL}}}
+	assert.Nil(t, request.Init())
+	started := time.Now()
+	_, err := s.send(ctx, request)
+	assert.NotNil(t, err)
+	assert.True(t, time.Since(started) < time.Second, "request should be cancelled with deadline")
+}
+
+// TestService_StressTestExpectResponses ensures lowercase responses expectation is validated together with load metrics
+func TestService_StressTestExpectResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	s := newServiceForTest()
+	ctx := newContextWithState(nil)
+	request := &LoadRequest{
+		SendRequest: &SendRequest{
+			Requests: []*Request{{Method: "GET", URL: server.URL}},
+			Expect: map[string]interface{}{
+				"responses":   []interface{}{map[string]interface{}{"Code": 500}},
+				"Percentiles": map[string]interface{}{"P99": "/[0..60000]/"},
+			},
+		},
+		ThreadCount: 1,
+	}
+	assert.Nil(t, request.Init())
+	response, err := s.stressTest(ctx, request)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.Equal(t, 1, response.Assert.Validation.FailedCount, "responses expectation should be validated")
+	assert.True(t, response.Assert.Validation.PassedCount > 0, "percentiles expectation should be validated")
+}
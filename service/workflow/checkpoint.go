@@ -0,0 +1,242 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/viant/afs"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+)
+
+const checkpointFile = "checkpoint.json"
+
+// Checkpoint represents persisted workflow progress used to resume interrupted run
+type Checkpoint struct {
+	SessionID    string
+	Combination  string `json:",omitempty"`
+	Workflow     string
+	Tasks        string
+	TaskName     string                 `json:",omitempty"`
+	Completed    []string               `json:",omitempty"`
+	State        map[string]interface{} `json:",omitempty"`
+	ProcessState map[string]interface{} `json:",omitempty"`
+	URL          string                 `json:"-"`
+	completed    map[string]bool
+	process      *model.Process
+	mux          sync.Mutex
+	fs           afs.Service
+}
+
+// IsCompleted returns true if task has been completed in previous run
+func (c *Checkpoint) IsCompleted(task *model.Task) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.completed[c.taskPath(task)]
+}
+
+// taskPath returns checkpoint task key, slash separated task path within the workflow i.e. build/compile
+func (c *Checkpoint) taskPath(task *model.Task) string {
+	if c.process != nil && c.process.Workflow != nil {
+		if result, ok := findTaskPath(c.process.Workflow.TasksNode, task); ok {
+			return result
+		}
+	}
+	return task.Name
+}
+
+func findTaskPath(node *model.TasksNode, task *model.Task) (string, bool) {
+	if node == nil {
+		return "", false
+	}
+	for _, candidate := range node.Tasks {
+		if candidate == task {
+			return candidate.Name, true
+		}
+		if result, ok := findTaskPath(candidate.TasksNode, task); ok {
+			return path.Join(candidate.Name, result), true
+		}
+	}
+	return "", false
+}
+
+func hasTaskPath(node *model.TasksNode, taskPath string) bool {
+	if node == nil {
+		return false
+	}
+	name, subPath := taskPath, ""
+	if index := strings.Index(taskPath, "/"); index != -1 {
+		name, subPath = taskPath[:index], taskPath[index+1:]
+	}
+	for _, candidate := range node.Tasks {
+		if candidate.Name != name {
+			continue
+		}
+		if subPath == "" || hasTaskPath(candidate.TasksNode, subPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// Complete marks supplied task as completed and persists checkpoint with the current state
+func (c *Checkpoint) Complete(ctx context.Context, process *model.Process, task *model.Task, state data.Map) error {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	taskPath := c.taskPath(task)
+	if !c.completed[taskPath] {
+		c.completed[taskPath] = true
+		c.Completed = append(c.Completed, taskPath)
+	}
+	c.TaskName = taskPath
+	c.State = encodableState(state)
+	if process.State != nil {
+		c.ProcessState = encodableState(process.State)
+	}
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return c.fs.Upload(ctx, c.URL, 0644, bytes.NewReader(content))
+}
+
+// Restore restores checkpoint state into supplied process and context state
+func (c *Checkpoint) Restore(process *model.Process, state data.Map) {
+	restoreState(c.State, state)
+	if process.State != nil {
+		restoreState(c.ProcessState, process.State)
+	}
+}
+
+func encodableState(state data.Map) map[string]interface{} {
+	var persistable = data.NewMap()
+	for k, v := range state {
+		if k == selfStateKey || isRuntimeValue(v) {
+			continue
+		}
+		persistable[k] = v
+	}
+	return persistable.AsEncodableMap()
+}
+
+func restoreState(source map[string]interface{}, target data.Map) {
+	for k, v := range source {
+		if k == selfStateKey || v == "func()" {
+			continue
+		}
+		if current, ok := target[k]; ok && (toolbox.IsFunc(current) || isRuntimeValue(current)) {
+			continue
+		}
+		target[k] = v
+	}
+}
+
+func isRuntimeValue(value interface{}) bool {
+	switch value.(type) {
+	case *endly.Context, *model.Process:
+		return true
+	}
+	return false
+}
+
+func (c *Checkpoint) init() {
+	c.completed = make(map[string]bool)
+	for _, task := range c.Completed {
+		c.completed[task] = true
+	}
+}
+
+// CheckpointURL returns checkpoint location for supplied log directory, session and optional matrix combination ID
+func CheckpointURL(logDirectory, sessionID, combination string) string {
+	if logDirectory == "" {
+		logDirectory = "logs"
+	}
+	return path.Join(logDirectory, sessionID, combination, checkpointFile)
+}
+
+// LoadCheckpoint loads checkpoint for supplied log directory, session and optional matrix combination ID
+func LoadCheckpoint(ctx context.Context, fs afs.Service, logDirectory, sessionID, combination string) (*Checkpoint, error) {
+	URL := CheckpointURL(logDirectory, sessionID, combination)
+	content, err := fs.DownloadWithURL(ctx, URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint: %v, %w", URL, err)
+	}
+	result := &Checkpoint{}
+	if err = json.Unmarshal(content, result); err != nil {
+		return nil, fmt.Errorf("failed to decode checkpoint: %v, %w", URL, err)
+	}
+	result.URL = URL
+	result.fs = fs
+	result.init()
+	return result, nil
+}
+
+// NewCheckpoint creates a new checkpoint
+func NewCheckpoint(fs afs.Service, logDirectory, sessionID, combination string, workflow *model.Workflow, tasks string) *Checkpoint {
+	result := &Checkpoint{
+		SessionID:   sessionID,
+		Combination: combination,
+		Workflow:    workflow.Name,
+		Tasks:       tasks,
+		URL:         CheckpointURL(logDirectory, sessionID, combination),
+		fs:          fs,
+	}
+	result.init()
+	return result
+}
+
+// validate checks that resumed run matches checkpoint workflow, tasks and completed task paths
+func (c *Checkpoint) validate(request *RunRequest, workflow *model.Workflow) error {
+	if c.Workflow != workflow.Name {
+		return fmt.Errorf("checkpoint %v was created for workflow: %v, but had: %v", c.URL, c.Workflow, workflow.Name)
+	}
+	if c.Tasks != request.Tasks {
+		return fmt.Errorf("checkpoint %v was created for tasks: %v, but had: %v", c.URL, c.Tasks, request.Tasks)
+	}
+	for _, taskPath := range c.Completed {
+		if !hasTaskPath(workflow.TasksNode, taskPath) {
+			return fmt.Errorf("checkpoint %v completed task: %v was not found in workflow: %v", c.URL, taskPath, workflow.Name)
+		}
+	}
+	return nil
+}
+
+var checkpointKey = (*Checkpoint)(nil)
+
+// processCheckpoint returns checkpoint owned by supplied process
+func processCheckpoint(context *endly.Context, process *model.Process) *Checkpoint {
+	if !context.Contains(checkpointKey) {
+		return nil
+	}
+	var result *Checkpoint
+	context.GetInto(checkpointKey, &result)
+	if result == nil || result.process != process {
+		return nil
+	}
+	return result
+}
+
+func (s *Service) initCheckpoint(context *endly.Context, request *RunRequest, workflow *model.Workflow) (*Checkpoint, error) {
+	if !request.Checkpoint && request.Resume == "" {
+		return nil, nil
+	}
+	if request.Resume == "" {
+		return NewCheckpoint(s.fs, request.LogDirectory, context.SessionID, request.combination, workflow, request.Tasks), nil
+	}
+	result, err := LoadCheckpoint(context.Background(), s.fs, request.LogDirectory, request.Resume, request.combination)
+	if err != nil {
+		return nil, err
+	}
+	if err = result.validate(request, workflow); err != nil {
+		return nil, err
+	}
+	context.Publish(NewResumeEvent(result))
+	return result, nil
+}
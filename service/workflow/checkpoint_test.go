@@ -0,0 +1,108 @@
+package workflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/afs"
+)
+
+const checkpointPipeline = `pipeline:
+  build:
+    app:
+      compile:
+        action: probe:call
+        name: build
+  deploy:
+    app:
+      compile:
+        action: probe:call
+        name: deploy
+        failure: true
+`
+
+// sessionIDs returns session directories created in log directory
+func sessionIDs(t *testing.T, logDirectory string) []string {
+	entries, err := os.ReadDir(logDirectory)
+	assert.Nil(t, err)
+	var result = make([]string, 0)
+	for _, entry := range entries {
+		result = append(result, entry.Name())
+	}
+	return result
+}
+
+func TestService_Checkpoint(t *testing.T) {
+	logDirectory := t.TempDir()
+	probe, _, response := runPipeline(t, checkpointPipeline, func(request *RunRequest) {
+		request.Checkpoint = true
+		request.LogDirectory = logDirectory
+	})
+	assert.NotEqual(t, "", response.Error)
+	assert.EqualValues(t, []string{"build", "deploy"}, probe.Calls())
+	sessions := sessionIDs(t, logDirectory)
+	if !assert.Equal(t, 1, len(sessions)) {
+		return
+	}
+	checkpoint, err := LoadCheckpoint(context.Background(), afs.New(), logDirectory, sessions[0], "")
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.EqualValues(t, []string{"build/app/compile", "build/app", "build"}, checkpoint.Completed)
+	assert.EqualValues(t, "build", checkpoint.TaskName)
+
+	recovered := strings.Replace(checkpointPipeline, "failure: true", "failure: false", 1)
+	probe, _, response = runPipeline(t, recovered, func(request *RunRequest) {
+		request.Resume = sessions[0]
+		request.LogDirectory = logDirectory
+	})
+	assert.Equal(t, "", response.Error)
+	assert.EqualValues(t, []string{"deploy"}, probe.Calls(), "completed tasks should be skipped")
+
+	probe, _, response = runPipeline(t, checkpointPipeline, func(request *RunRequest) {
+		request.Resume = sessions[0]
+		request.LogDirectory = logDirectory
+		request.Tasks = "deploy"
+	})
+	assert.Contains(t, response.Error, "was created for tasks")
+	assert.EqualValues(t, []string{}, probe.Calls())
+
+	probe, _, response = runPipeline(t, `pipeline:
+  deploy:
+    action: probe:call
+    name: deploy
+`, func(request *RunRequest) {
+		request.Resume = sessions[0]
+		request.LogDirectory = logDirectory
+	})
+	assert.Contains(t, response.Error, "was not found in workflow")
+	assert.EqualValues(t, []string{}, probe.Calls())
+}
+
+func TestService_MatrixCheckpoint(t *testing.T) {
+	logDirectory := t.TempDir()
+	recovered := strings.Replace(checkpointPipeline, "failure: true", "failure: false", 1)
+	_, _, response := runPipeline(t, recovered, func(request *RunRequest) {
+		request.Checkpoint = true
+		request.LogDirectory = logDirectory
+		request.Matrix = map[string][]interface{}{"db": {"mysql", "pg"}}
+	})
+	assert.Equal(t, "", response.Error)
+	sessions := sessionIDs(t, logDirectory)
+	if !assert.Equal(t, 1, len(sessions)) {
+		return
+	}
+	for _, combination := range []string{"db-mysql", "db-pg"} {
+		_, err := os.Stat(filepath.Join(logDirectory, sessions[0], combination, checkpointFile))
+		assert.Nil(t, err, combination)
+		checkpoint, err := LoadCheckpoint(context.Background(), afs.New(), logDirectory, sessions[0], combination)
+		if assert.Nil(t, err, combination) {
+			assert.Equal(t, combination, checkpoint.Combination)
+			assert.EqualValues(t, []string{"build/app/compile", "build/app", "build", "deploy/app/compile", "deploy/app", "deploy"}, checkpoint.Completed)
+		}
+	}
+}
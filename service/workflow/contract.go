@@ -35,8 +35,18 @@ type RunRequest struct {
 	TagIDs            string `description:"coma separated TagID list, if present in a task, only matched runs, other task runWorkflow as normal"`
 	Tasks             string `required:"true" description:"coma separated task list, if empty or '*' runs all tasks sequentially"` //tasks to runWorkflow with coma separated list or '*', or empty string for all tasks
 	Interactive       bool
+	Checkpoint        bool                     `description:"flag to persist checkpoint (state and completed tasks) after each completed task in log directory"`
+	Resume            string                   `description:"session ID of interrupted run to resume from the first unfinished task, with checkpoint state restored"`
+	TimeoutMs         int                      `description:"optional workflow max execution time, when exceeded running action fails with timeout error"`
+	Debug             bool                     `description:"flag to run workflow in interactive debugger, without breakpoints debugger pauses before each action"`
+	Breakpoints       []string                 `description:"debugger breakpoints: workflow/task/action selector (with * wildcard) or #tagID"`
+	DAPAddress        string                   `description:"Debug Adapter Protocol server TCP address i.e :4711, when set workflow waits for debug client before running"`
+	DryRun            bool                     `description:"flag to render and validate fully resolved service requests without executing them, service actions return stubbed responses"`
+	Matrix            map[string][]interface{} `description:"parameter axes, workflow runs once per axes values combination with combination values merged into params"`
+	MatrixParallel    bool                     `description:"flag to run matrix combinations in parallel, each combination runs with its own cloned context"`
 	*model.Inlined
-	workflow *model.Workflow //inline workflow from pipeline
+	workflow    *model.Workflow //inline workflow from pipeline
+	combination string          //matrix combination ID
 }
 
 // Init initialises request
@@ -95,6 +105,11 @@ func (r *RunRequest) Init() (err error) {
 
 // Validate checks if request is valid
 func (r *RunRequest) Validate() error {
+	for axis, values := range r.Matrix {
+		if len(values) == 0 {
+			return fmt.Errorf("matrix axis %v was empty", axis)
+		}
+	}
 	if r.workflow != nil {
 		return r.workflow.Validate()
 	}
@@ -131,6 +146,7 @@ func NewRunRequestFromURL(URL string) (*RunRequest, error) {
 type RunResponse struct {
 	Data      map[string]interface{} //  data populated by  .Post variable section.
 	SessionID string                 //session id
+	Matrix    []*MatrixResult        `json:",omitempty" yaml:",omitempty"` // matrix combination results
 }
 
 // RegisterRequest represents workflow register request
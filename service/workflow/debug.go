@@ -0,0 +1,76 @@
+package workflow
+
+import (
+	"fmt"
+
+	"github.com/viant/endly"
+	"github.com/viant/endly/internal/debug"
+	"github.com/viant/endly/internal/debug/dap"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+)
+
+func debugStep(process *model.Process, action *model.Action) debug.Step {
+	var result = debug.Step{
+		Action: action.Name,
+		TagID:  action.TagID,
+	}
+	if result.Action == "" {
+		result.Action = fmt.Sprintf("%v:%v", action.Service, action.Action)
+	}
+	if process.Workflow != nil {
+		result.Workflow = process.Workflow.Name
+	}
+	if process.Task != nil {
+		result.TaskName = process.Task.Name
+	}
+	return result
+}
+
+// runDebuggable runs service request pausing in debugger before and after execution, re-run rebuilds request from the current state
+func (s *Service) runDebuggable(context *endly.Context, process *model.Process, action *model.Action, request interface{}, buildRequest func() (interface{}, error), response *endly.ServiceResponse) error {
+	debugger := context.Debugger
+	step := debugStep(process, action)
+	state := context.State()
+	if debugger.Before(step, request, state) == debug.Skip {
+		context.Publish(msg.NewStdoutEvent("debug", fmt.Sprintf("skipped %v", step)))
+		return nil
+	}
+	for {
+		err := endly.Run(context, request, response)
+		if debugger.After(step, request, response.Response, err, state) != debug.Rerun {
+			return err
+		}
+		if request, err = buildRequest(); err != nil {
+			return err
+		}
+	}
+}
+
+// enableDebuggerIfNeeded enables debugger for top level run, it returns function releasing debugger resources
+func (s *Service) enableDebuggerIfNeeded(context *endly.Context, request *RunRequest) (func(), error) {
+	if context.Debugger != nil || !(request.Debug || len(request.Breakpoints) > 0 || request.DAPAddress != "") {
+		return func() {}, nil
+	}
+	debugger := debug.NewDebugger()
+	debugger.EnableStepMode(request.DAPAddress == "" && len(request.Breakpoints) == 0)
+	for _, breakpoint := range request.Breakpoints {
+		debugger.SetBreakpoint(debug.ParseStep(breakpoint))
+	}
+	context.Debugger = debugger
+	if request.DAPAddress == "" {
+		return func() {}, nil
+	}
+	server := dap.New(debugger)
+	if err := server.Listen(request.DAPAddress); err != nil {
+		return nil, fmt.Errorf("failed to start DAP server: %w", err)
+	}
+	context.Publish(msg.NewStdoutEvent("debug", fmt.Sprintf("waiting for DAP client on %v", server.Addr())))
+	if err := server.Serve(); err != nil {
+		_ = server.Close()
+		return nil, err
+	}
+	return func() {
+		_ = server.Close()
+	}, nil
+}
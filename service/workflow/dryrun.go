@@ -0,0 +1,68 @@
+package workflow
+
+import (
+	"fmt"
+
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+)
+
+// dryRun represents dry-run mode marker stored in the context, it is inherited by nested workflows
+type dryRun struct{}
+
+var dryRunKey = (*dryRun)(nil)
+
+// dryRunActions represents workflow service actions that still run in dry-run mode to follow the control flow
+var dryRunActions = map[string]bool{
+	"run":    true,
+	"goto":   true,
+	"switch": true,
+	"nop":    true,
+	"print":  true,
+}
+
+func isDryRun(context *endly.Context) bool {
+	return context.Contains(dryRunKey)
+}
+
+// canRunDry returns true if supplied service action has to be rendered instead of being executed
+func canRunDry(context *endly.Context, serviceID, action string) bool {
+	if !isDryRun(context) {
+		return false
+	}
+	return !(serviceID == ServiceID && dryRunActions[action])
+}
+
+func (s *Service) enableDryRunIfNeeded(context *endly.Context, request *RunRequest) {
+	if request.DryRun && !isDryRun(context) {
+		_ = context.Put(dryRunKey, &dryRun{})
+	}
+}
+
+// runDry initialises and validates expanded request, publishes it and sets stubbed response without executing service action
+func (s *Service) runDry(context *endly.Context, activity *model.Activity, request interface{}, response *endly.ServiceResponse) error {
+	service, err := context.Service(activity.Service)
+	if err != nil {
+		return err
+	}
+	route, err := service.Route(activity.Action)
+	if err != nil {
+		return err
+	}
+	if initializer, ok := request.(endly.Initializer); ok {
+		if err = initializer.Init(); err != nil {
+			return endly.NewError(activity.Service, activity.Action, fmt.Errorf("init %T failed: %v", request, err))
+		}
+	}
+	if validator, ok := request.(endly.Validator); ok {
+		if err = validator.Validate(); err != nil {
+			return endly.NewError(activity.Service, activity.Action, fmt.Errorf("validation %T failed: %v", request, err))
+		}
+	}
+	context.Publish(NewDryRunEvent(activity, request))
+	response.Status = "ok"
+	if route.ResponseProvider != nil {
+		response.Response = route.ResponseProvider()
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_RunDry(t *testing.T) {
+	pipeline := `init:
+  target: e2e
+pipeline:
+  first:
+    action: probe:call
+    name: ${target}_first
+  second:
+    action: probe:call
+    name: second
+    failure: true
+  info:
+    action: print
+    message: done
+`
+	probe, recorder, response := runPipeline(t, pipeline, func(request *RunRequest) {
+		request.DryRun = true
+	})
+	assert.Equal(t, "", response.Error)
+	assert.EqualValues(t, []string{}, probe.Calls(), "service handlers should not be invoked")
+
+	recorder.mux.Lock()
+	defer recorder.mux.Unlock()
+	var rendered = make([]*DryRunEvent, 0)
+	for _, event := range recorder.events {
+		if dryRunEvent, ok := event.Value().(*DryRunEvent); ok {
+			rendered = append(rendered, dryRunEvent)
+		}
+	}
+	if assert.Equal(t, 2, len(rendered)) {
+		assert.Equal(t, "probe", rendered[0].Service)
+		assert.Equal(t, "call", rendered[0].Action)
+		assert.Equal(t, &probeRequest{Name: "e2e_first"}, rendered[0].Request)
+		assert.Equal(t, &probeRequest{Name: "second", Failure: true}, rendered[1].Request)
+	}
+}
@@ -1,8 +1,14 @@
 package workflow
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
 	"github.com/viant/toolbox/data"
+	"gopkg.in/yaml.v2"
 )
 
 // LoadedEvent represents workflow load event
@@ -41,6 +47,21 @@ func NewEndEvent(sessionID string) *EndEvent {
 	}
 }
 
+// LogFileEvent represents event log file written by workflow logger
+type LogFileEvent struct {
+	URL string
+}
+
+// Attachments returns log file location (CLI reporter interface)
+func (e *LogFileEvent) Attachments() []string {
+	return []string{e.URL}
+}
+
+// NewLogFileEvent creates a new LogFileEvent
+func NewLogFileEvent(URL string) *LogFileEvent {
+	return &LogFileEvent{URL: URL}
+}
+
 // AsyncEvent represents an async action event.
 type AsyncEvent struct {
 	ServiceAction *model.Action
@@ -50,3 +71,215 @@ type AsyncEvent struct {
 func NewAsyncEvent(action *model.Action) *AsyncEvent {
 	return &AsyncEvent{action}
 }
+
+// ResumeEvent represents workflow resume from checkpoint event
+type ResumeEvent struct {
+	SessionID string
+	Workflow  string
+	Completed []string
+}
+
+// NewResumeEvent creates a new ResumeEvent
+func NewResumeEvent(checkpoint *Checkpoint) *ResumeEvent {
+	return &ResumeEvent{
+		SessionID: checkpoint.SessionID,
+		Workflow:  checkpoint.Workflow,
+		Completed: checkpoint.Completed,
+	}
+}
+
+// RetryEvent represents failed action attempt that is going to be retried
+type RetryEvent struct {
+	TagID       string
+	Service     string
+	Action      string
+	Attempt     int
+	MaxAttempts int
+	Error       string
+	DelayMs     int
+}
+
+// Messages returns messages
+func (e *RetryEvent) Messages() []*msg.Message {
+	text := fmt.Sprintf("attempt %v/%v failed: %v, retrying in %v ms", e.Attempt, e.MaxAttempts, e.Error, e.DelayMs)
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(text, msg.MessageStyleError), msg.NewStyled("retry", msg.MessageStyleGeneric)),
+	}
+}
+
+// NewRetryEvent creates a new RetryEvent
+func NewRetryEvent(action *model.Action, attempt, maxAttempts int, err error, delay time.Duration) *RetryEvent {
+	return &RetryEvent{
+		TagID:       action.TagID,
+		Service:     action.Service,
+		Action:      action.Action,
+		Attempt:     attempt,
+		MaxAttempts: maxAttempts,
+		Error:       err.Error(),
+		DelayMs:     int(delay / time.Millisecond),
+	}
+}
+
+// TimeoutEvent represents workflow node timeout event
+type TimeoutEvent struct {
+	Error string
+}
+
+// Messages returns messages
+func (e *TimeoutEvent) Messages() []*msg.Message {
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(e.Error, msg.MessageStyleError), msg.NewStyled("timeout", msg.MessageStyleError)),
+	}
+}
+
+// NewTimeoutEvent creates a new TimeoutEvent
+func NewTimeoutEvent(err error) *TimeoutEvent {
+	return &TimeoutEvent{Error: err.Error()}
+}
+
+// DryRunEvent represents fully resolved service request rendered in dry-run mode
+type DryRunEvent struct {
+	TagID   string
+	Service string
+	Action  string
+	Request interface{}
+}
+
+// Messages returns messages
+func (e *DryRunEvent) Messages() []*msg.Message {
+	info := ""
+	var request = make(map[string]interface{})
+	if err := toolbox.DefaultConverter.AssignConverted(&request, e.Request); err == nil {
+		if content, err := yaml.Marshal(toolbox.DeleteEmptyKeys(request)); err == nil {
+			info = string(content)
+		}
+	}
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(fmt.Sprintf("%v:%v", e.Service, e.Action), msg.MessageStyleGeneric),
+			msg.NewStyled("dry-run", msg.MessageStyleGeneric),
+			msg.NewStyled(info, msg.MessageStyleInput)),
+	}
+}
+
+// NewDryRunEvent creates a new DryRunEvent
+func NewDryRunEvent(activity *model.Activity, request interface{}) *DryRunEvent {
+	result := &DryRunEvent{
+		Service: activity.Service,
+		Action:  activity.Action,
+		Request: request,
+	}
+	if activity.MetaTag != nil {
+		result.TagID = activity.TagID
+	}
+	return result
+}
+
+// MatrixStartEvent represents matrix combination run start event
+type MatrixStartEvent struct {
+	Name   string
+	Index  int
+	Total  int
+	Params map[string]interface{}
+}
+
+// Messages returns messages
+func (e *MatrixStartEvent) Messages() []*msg.Message {
+	return []*msg.Message{
+		msg.NewMessage(msg.NewStyled(fmt.Sprintf("[%v/%v] %v", e.Index+1, e.Total, e.Name), msg.MessageStyleGeneric),
+			msg.NewStyled("matrix", msg.MessageStyleGroup)),
+	}
+}
+
+// NewMatrixStartEvent creates a new MatrixStartEvent
+func NewMatrixStartEvent(combination *MatrixCombination, total int) *MatrixStartEvent {
+	return &MatrixStartEvent{
+		Name:   combination.Name(),
+		Index:  combination.Index,
+		Total:  total,
+		Params: combination.Params,
+	}
+}
+
+// MatrixEndEvent represents matrix combination run end event
+type MatrixEndEvent struct {
+	*MatrixResult
+}
+
+// Messages returns messages
+func (e *MatrixEndEvent) Messages() []*msg.Message {
+	return []*msg.Message{matrixResultMessage(e.MatrixResult)}
+}
+
+// NewMatrixEndEvent creates a new MatrixEndEvent
+func NewMatrixEndEvent(result *MatrixResult) *MatrixEndEvent {
+	return &MatrixEndEvent{MatrixResult: result}
+}
+
+// MatrixSummaryEvent represents matrix run summary event
+type MatrixSummaryEvent struct {
+	Workflow  string
+	Results   []*MatrixResult
+	Passed    int
+	Failed    int
+	Errors    int
+	ElapsedMs int
+}
+
+// Messages returns messages
+func (e *MatrixSummaryEvent) Messages() []*msg.Message {
+	style := msg.MessageStyleSuccess
+	if e.Failed+e.Errors > 0 {
+		style = msg.MessageStyleError
+	}
+	text := fmt.Sprintf("%v: passed %v/%v combinations, elapsed: %v ms", e.Workflow, e.Passed, len(e.Results), e.ElapsedMs)
+	var result = []*msg.Message{
+		msg.NewMessage(msg.NewStyled(text, style), msg.NewStyled("matrix", msg.MessageStyleGroup)),
+	}
+	for _, matrixResult := range e.Results {
+		result = append(result, matrixResultMessage(matrixResult))
+	}
+	return result
+}
+
+// NewMatrixSummaryEvent creates a new MatrixSummaryEvent
+func NewMatrixSummaryEvent(workflow string, results []*MatrixResult) *MatrixSummaryEvent {
+	result := &MatrixSummaryEvent{
+		Workflow: workflow,
+		Results:  results,
+	}
+	for _, matrixResult := range results {
+		switch {
+		case matrixResult.Error != "":
+			result.Errors++
+		case matrixResult.Failed > 0:
+			result.Failed++
+		default:
+			result.Passed++
+		}
+	}
+	if len(results) > 0 {
+		var start, end = results[0].StartTime, results[0].StartTime
+		for _, matrixResult := range results {
+			if matrixResult.StartTime.Before(start) {
+				start = matrixResult.StartTime
+			}
+			if finished := matrixResult.StartTime.Add(time.Duration(matrixResult.ElapsedMs) * time.Millisecond); finished.After(end) {
+				end = finished
+			}
+		}
+		result.ElapsedMs = int(end.Sub(start) / time.Millisecond)
+	}
+	return result
+}
+
+func matrixResultMessage(result *MatrixResult) *msg.Message {
+	style := msg.MessageStyleSuccess
+	if result.HasFailure() {
+		style = msg.MessageStyleError
+	}
+	text := fmt.Sprintf("%v: %v, passed %v/%v, elapsed: %v ms", result.Name, result.Status, result.Passed, result.Passed+result.Failed, result.ElapsedMs)
+	if result.Error != "" {
+		text += ", error: " + result.Error
+	}
+	return msg.NewMessage(msg.NewStyled(text, style), msg.NewStyled("matrix", msg.MessageStyleGroup))
+}
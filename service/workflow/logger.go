@@ -246,13 +246,18 @@ func (l *Logger) handlerError(err error) {
 
 // OnEvent handles supplied event.
 func (l *Logger) OnEvent(event msg.Event) {
+	l.onEvent(event)
+}
+
+// onEvent writes supplied event, it returns written file name
+func (l *Logger) onEvent(event msg.Event) string {
 	l.mutex.Lock()
 	defer l.mutex.Unlock()
 	l.processEvent(event)
 
 	// Skip writing certain structural events
 	if !l.shouldWriteEvent(event) {
-		return
+		return ""
 	}
 
 	groupDir := l.groupDir()
@@ -262,13 +267,13 @@ func (l *Logger) OnEvent(event msg.Event) {
 		err := os.MkdirAll(parent, 0744)
 		if err != nil {
 			l.handlerError(err)
-			return
+			return ""
 		}
 	}
 	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		l.handlerError(err)
-		return
+		return ""
 	}
 	defer func() { _ = file.Close() }()
 	value := event.Value()
@@ -306,9 +311,28 @@ func (l *Logger) OnEvent(event msg.Event) {
 	buf, err := json.MarshalIndent(wrapper, "", "\t")
 	if err != nil {
 		l.handlerError(err)
-		return
+		return ""
 	}
 	_, _ = file.Write(buf)
+	return filename
+}
+
+// isFailureEvent returns true if supplied event reports an error, failed action or failed validation
+func isFailureEvent(event msg.Event) bool {
+	switch value := event.Value().(type) {
+	case *msg.ErrorEvent:
+		return true
+	case *model.ActivityEndEvent:
+		activity, ok := value.Response.(*model.Activity)
+		return ok && activity.Error != ""
+	case asserted:
+		for _, validation := range value.Assertion() {
+			if validation.FailedCount > 0 {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // AsEventListener returns an event Listener
@@ -317,7 +341,9 @@ func (l *Logger) AsEventListener() msg.Listener {
 		if l.Listener != nil {
 			l.Listener(event)
 		}
-		l.OnEvent(event)
+		if filename := l.onEvent(event); filename != "" && l.Listener != nil && isFailureEvent(event) {
+			l.Listener(msg.NewEvent(NewLogFileEvent(filename)))
+		}
 	}
 }
 
@@ -0,0 +1,41 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_LogFileEvent(t *testing.T) {
+	var useCases = []struct {
+		description string
+		failure     bool
+		expectFiles bool
+	}{
+		{description: "passing action logs are not attached"},
+		{description: "failing action logs are attached", failure: true, expectFiles: true},
+	}
+	for _, useCase := range useCases {
+		pipeline := `pipeline:
+  check:
+    action: probe:call
+    name: check
+    failure: ` + map[bool]string{true: "true", false: "false"}[useCase.failure] + `
+`
+		logDirectory := t.TempDir()
+		_, recorder, response := runPipeline(t, pipeline, func(request *RunRequest) {
+			request.EnableLogging = true
+			request.LogDirectory = logDirectory
+		})
+		assert.Equal(t, useCase.failure, response.Error != "", useCase.description)
+		var logFiles = 0
+		recorder.mux.Lock()
+		for _, event := range recorder.events {
+			if _, ok := event.Value().(*LogFileEvent); ok {
+				logFiles++
+			}
+		}
+		recorder.mux.Unlock()
+		assert.Equal(t, useCase.expectFiles, logFiles > 0, useCase.description)
+	}
+}
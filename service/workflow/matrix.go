@@ -0,0 +1,229 @@
+package workflow
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/viant/assertly"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+)
+
+var matrixIDUnsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// asserted represents response with validation result
+type asserted interface {
+	Assertion() []*assertly.Validation
+}
+
+// MatrixCombination represents one combination of matrix axes values
+type MatrixCombination struct {
+	Index  int
+	Axes   []string
+	Params map[string]interface{}
+}
+
+// Name returns combination name i.e. db=mysql,version=1.0
+func (c *MatrixCombination) Name() string {
+	var pairs = make([]string, 0, len(c.Axes))
+	for _, axis := range c.Axes {
+		pairs = append(pairs, fmt.Sprintf("%v=%v", axis, toolbox.AsString(c.Params[axis])))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// ID returns file system safe combination identifier i.e. db-mysql_version-1.0
+func (c *MatrixCombination) ID() string {
+	var pairs = make([]string, 0, len(c.Axes))
+	for _, axis := range c.Axes {
+		pair := fmt.Sprintf("%v-%v", axis, toolbox.AsString(c.Params[axis]))
+		pairs = append(pairs, matrixIDUnsafeChars.ReplaceAllString(pair, "_"))
+	}
+	return strings.Join(pairs, "_")
+}
+
+// ExpandMatrix returns all axes values combinations, axes are ordered by name with the first axis changing the slowest
+func ExpandMatrix(matrix map[string][]interface{}) []*MatrixCombination {
+	if len(matrix) == 0 {
+		return nil
+	}
+	var axes = make([]string, 0, len(matrix))
+	for axis := range matrix {
+		axes = append(axes, axis)
+	}
+	sort.Strings(axes)
+	var result = []*MatrixCombination{{Axes: axes, Params: map[string]interface{}{}}}
+	for _, axis := range axes {
+		var expanded = make([]*MatrixCombination, 0, len(result)*len(matrix[axis]))
+		for _, combination := range result {
+			for _, value := range matrix[axis] {
+				params := make(map[string]interface{}, len(combination.Params)+1)
+				for k, v := range combination.Params {
+					params[k] = v
+				}
+				params[axis] = value
+				expanded = append(expanded, &MatrixCombination{Axes: axes, Params: params})
+			}
+		}
+		result = expanded
+	}
+	for i := range result {
+		result[i].Index = i
+	}
+	return result
+}
+
+// MatrixResult represents matrix combination run result
+type MatrixResult struct {
+	Name        string
+	Params      map[string]interface{}
+	LogSubdir   string `json:",omitempty" yaml:",omitempty"`
+	Status      string
+	Error       string `json:",omitempty" yaml:",omitempty"`
+	StartTime   time.Time
+	ElapsedMs   int
+	Passed      int
+	Failed      int
+	Data        map[string]interface{} `json:",omitempty" yaml:",omitempty"`
+	Validations []*assertly.Validation `json:"-" yaml:"-"`
+	mux         sync.Mutex
+}
+
+// HasFailure returns true if combination run failed with error or failed validation
+func (r *MatrixResult) HasFailure() bool {
+	return r.Error != "" || r.Failed > 0
+}
+
+func (r *MatrixResult) addValidations(validations ...*assertly.Validation) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	for _, validation := range validations {
+		if validation == nil || validation.PassedCount+validation.FailedCount == 0 {
+			continue
+		}
+		r.Validations = append(r.Validations, validation)
+		r.Passed += validation.PassedCount
+		r.Failed += validation.FailedCount
+	}
+}
+
+// matrixWorkflowName returns request name, or workflow name (asset name for unnamed inline workflow)
+func matrixWorkflowName(request *RunRequest) string {
+	if request.Name != "" {
+		return request.Name
+	}
+	if request.workflow != nil && request.workflow.Name != "" {
+		return request.workflow.Name
+	}
+	for _, URL := range []string{request.AssetURL, request.URL} {
+		if URL != "" {
+			name := path.Base(URL)
+			return strings.TrimSuffix(name, path.Ext(name))
+		}
+	}
+	return ""
+}
+
+// runMatrix runs workflow once per matrix combination, each combination runs with a cloned context
+func (s *Service) runMatrix(context *endly.Context, request *RunRequest) (*RunResponse, error) {
+	combinations := ExpandMatrix(request.Matrix)
+	response := &RunResponse{
+		Data:      make(map[string]interface{}),
+		SessionID: context.SessionID,
+		Matrix:    make([]*MatrixResult, len(combinations)),
+	}
+	logSubdir := request.LogSubdir
+	if logSubdir == "" {
+		logSubdir = context.SessionID
+	}
+	if !request.MatrixParallel {
+		for i, combination := range combinations {
+			response.Matrix[i] = s.runMatrixCombination(context.Clone(), request, combination, logSubdir, len(combinations))
+		}
+	} else {
+		group := &sync.WaitGroup{}
+		group.Add(len(combinations))
+		var combinationEvents = make([]*msg.Events, len(combinations))
+		for i := range combinations {
+			combinationContext := context.Clone()
+			_ = combinationContext.Replace(processesKey, processes(context).Clone())
+			combinationEvents[i] = combinationContext.MakeAsyncSafe()
+			go func(i int, combinationContext *endly.Context) {
+				defer group.Done()
+				response.Matrix[i] = s.runMatrixCombination(combinationContext, request, combinations[i], logSubdir, len(combinations))
+			}(i, combinationContext)
+		}
+		group.Wait()
+		for _, events := range combinationEvents {
+			for _, event := range events.Events {
+				context.Publish(event)
+			}
+		}
+	}
+	summary := NewMatrixSummaryEvent(matrixWorkflowName(request), response.Matrix)
+	context.Publish(summary)
+	if summary.Errors > 0 {
+		var failed = make([]string, 0)
+		for _, result := range response.Matrix {
+			if result.Error != "" {
+				failed = append(failed, fmt.Sprintf("[%v]: %v", result.Name, result.Error))
+			}
+		}
+		return response, fmt.Errorf("matrix %v/%v combinations failed: %v", summary.Errors, len(combinations), strings.Join(failed, "; "))
+	}
+	return response, nil
+}
+
+func (s *Service) runMatrixCombination(context *endly.Context, request *RunRequest, combination *MatrixCombination, logSubdir string, total int) *MatrixResult {
+	result := &MatrixResult{
+		Name:      combination.Name(),
+		Params:    combination.Params,
+		StartTime: time.Now(),
+	}
+	listener := context.Listener
+	context.Listener = func(event msg.Event) {
+		if response, ok := event.Value().(asserted); ok {
+			result.addValidations(response.Assertion()...)
+		}
+		if listener != nil {
+			listener(event)
+		}
+	}
+	combinationRequest := *request
+	combinationRequest.Matrix = nil
+	combinationRequest.MatrixParallel = false
+	combinationRequest.Async = false
+	combinationRequest.combination = combination.ID()
+	combinationRequest.Params = make(map[string]interface{}, len(request.Params)+len(combination.Params))
+	for k, v := range request.Params {
+		combinationRequest.Params[k] = v
+	}
+	for k, v := range combination.Params {
+		combinationRequest.Params[k] = v
+	}
+	if request.EnableLogging {
+		combinationRequest.LogSubdir = path.Join(logSubdir, combination.ID())
+		result.LogSubdir = combinationRequest.LogSubdir
+	}
+	context.Publish(NewMatrixStartEvent(combination, total))
+	response, err := s.runWorkflow(context, &combinationRequest)
+	result.ElapsedMs = int(time.Since(result.StartTime) / time.Millisecond)
+	result.Status = "ok"
+	if err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+	} else if result.Failed > 0 {
+		result.Status = "failed"
+	}
+	if response != nil {
+		result.Data = response.Data
+	}
+	context.Publish(NewMatrixEndEvent(result))
+	return result
+}
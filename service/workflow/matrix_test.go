@@ -0,0 +1,34 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_RunMatrix(t *testing.T) {
+	pipeline := `pipeline:
+  check:
+    action: probe:call
+    name: ${params.env}
+`
+	probe, recorder, response := runPipeline(t, pipeline, func(request *RunRequest) {
+		request.Name = ""
+		request.Matrix = map[string][]interface{}{"env": {"dev", "prod"}}
+	})
+	assert.Equal(t, "", response.Error)
+	assert.EqualValues(t, []string{"dev", "prod"}, probe.Calls())
+
+	recorder.mux.Lock()
+	defer recorder.mux.Unlock()
+	var summary *MatrixSummaryEvent
+	for _, event := range recorder.events {
+		if value, ok := event.Value().(*MatrixSummaryEvent); ok {
+			summary = value
+		}
+	}
+	if assert.NotNil(t, summary) {
+		assert.Equal(t, "pipeline", summary.Workflow, "unnamed run should use workflow name")
+		assert.Equal(t, 2, len(summary.Results))
+	}
+}
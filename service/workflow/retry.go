@@ -0,0 +1,46 @@
+package workflow
+
+import (
+	"time"
+
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+)
+
+// runActionWithRetry runs action, failed attempts are retried according to action retry policy
+func (s *Service) runActionWithRetry(context *endly.Context, action *model.Action, process *model.Process) (response map[string]interface{}, err error) {
+	retry := action.Retry
+	for attempt := 1; ; attempt++ {
+		if response, err = s.runActionAttempt(context, action, process, attempt); err == nil {
+			return response, nil
+		}
+		shouldRetry, e := retry.ShouldRetry(context, attempt, err)
+		if e != nil {
+			return nil, e
+		}
+		if !shouldRetry || process.IsTerminated() {
+			return nil, err
+		}
+		delay := retry.Delay(attempt)
+		context.Publish(NewRetryEvent(action, attempt, retry.MaxAttempts, err, delay))
+		if err = backoff(context, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// backoff waits for supplied retry delay, it returns timeout error once workflow node deadline is exceeded
+func backoff(context *endly.Context, delay time.Duration) error {
+	if context.IsLoggingEnabled() {
+		context.Publish(msg.NewSleepEvent(int(delay / time.Millisecond)))
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-context.Background().Done():
+		return context.TimeoutError()
+	}
+}
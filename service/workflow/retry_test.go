@@ -0,0 +1,52 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_RunActionWithRetry(t *testing.T) {
+	pipeline := `pipeline:
+  flaky:
+    action: probe:call
+    name: flaky
+    failure: true
+    retry:
+      maxAttempts: 3
+      delayMs: 10
+`
+	probe, recorder, response := runPipeline(t, pipeline, nil)
+	assert.NotEqual(t, "", response.Error)
+	assert.EqualValues(t, []string{"flaky", "flaky", "flaky"}, probe.Calls())
+	var retries = 0
+	recorder.mux.Lock()
+	for _, event := range recorder.events {
+		if _, ok := event.Value().(*RetryEvent); ok {
+			retries++
+		}
+	}
+	recorder.mux.Unlock()
+	assert.Equal(t, 2, retries)
+}
+
+func TestService_RunActionWithRetryTimeout(t *testing.T) {
+	pipeline := `pipeline:
+  flaky:
+    action: probe:call
+    name: flaky
+    failure: true
+    retry:
+      maxAttempts: 5
+      delayMs: 1000
+`
+	started := time.Now()
+	probe, _, response := runPipeline(t, pipeline, func(request *RunRequest) {
+		request.TimeoutMs = 200
+	})
+	assert.True(t, time.Since(started) < 800*time.Millisecond, "backoff should honor deadline")
+	assert.True(t, strings.Contains(response.Error, "timed out"), response.Error)
+	assert.EqualValues(t, []string{"flaky"}, probe.Calls())
+}
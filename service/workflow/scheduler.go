@@ -0,0 +1,249 @@
+package workflow
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox"
+	"github.com/viant/toolbox/data"
+)
+
+const defaultTaskConcurrency = 4
+
+// graphNode represents a task scheduled with its dependencies
+type graphNode struct {
+	task            *model.Task
+	process         *model.Process
+	index           int
+	pending         int
+	dependents      []*graphNode
+	context         *endly.Context
+	snapshot        data.Map
+	processSnapshot data.Map
+	events          *msg.Events
+	err             error
+	finished        bool
+	published       bool
+}
+
+// taskGraph represents tasks dependency graph
+type taskGraph struct {
+	nodes []*graphNode
+	ready []*graphNode
+}
+
+func (g *taskGraph) schedule(node *graphNode) {
+	g.ready = append(g.ready, node)
+	sort.Slice(g.ready, func(i, j int) bool {
+		return g.ready[i].index < g.ready[j].index
+	})
+}
+
+func (g *taskGraph) next() *graphNode {
+	result := g.ready[0]
+	g.ready = g.ready[1:]
+	return result
+}
+
+// resolve releases dependents of supplied finished node
+func (g *taskGraph) resolve(node *graphNode) {
+	for _, dependent := range node.dependents {
+		dependent.pending--
+		if dependent.pending == 0 {
+			g.schedule(dependent)
+		}
+	}
+}
+
+// publish publishes buffered task events in task declaration order
+func (g *taskGraph) publish(context *endly.Context) {
+	for _, node := range g.nodes {
+		if node.published {
+			continue
+		}
+		if !node.finished {
+			return
+		}
+		node.published = true
+		if node.events == nil {
+			continue
+		}
+		for _, event := range node.events.Events {
+			context.Publish(event)
+		}
+	}
+}
+
+func newTaskGraph(tasks *model.TasksNode) (*taskGraph, error) {
+	result := &taskGraph{}
+	var byName = make(map[string]*graphNode)
+	for _, task := range tasks.Tasks {
+		if task.Name == tasks.OnErrorTask || task.Name == tasks.DeferredTask {
+			continue
+		}
+		node := &graphNode{task: task, index: len(result.nodes)}
+		result.nodes = append(result.nodes, node)
+		byName[task.Name] = node
+	}
+	for _, node := range result.nodes {
+		for _, name := range node.task.DependsOn {
+			dependency, ok := byName[name]
+			if !ok { //dependency was not selected to run
+				continue
+			}
+			node.pending++
+			dependency.dependents = append(dependency.dependents, node)
+		}
+	}
+	if err := result.checkCycles(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (g *taskGraph) checkCycles() error {
+	var pending = make(map[*graphNode]int)
+	var queue = make([]*graphNode, 0)
+	for _, node := range g.nodes {
+		pending[node] = node.pending
+		if node.pending == 0 {
+			queue = append(queue, node)
+		}
+	}
+	visited := 0
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		visited++
+		for _, dependent := range node.dependents {
+			if pending[dependent]--; pending[dependent] == 0 {
+				queue = append(queue, dependent)
+			}
+		}
+	}
+	if visited == len(g.nodes) {
+		return nil
+	}
+	var cycle = make([]string, 0)
+	for _, node := range g.nodes {
+		if pending[node] > 0 {
+			cycle = append(cycle, node.task.Name)
+		}
+	}
+	return fmt.Errorf("detected dependsOn cycle between tasks: %v", cycle)
+}
+
+// mergeState applies top level state changes made by a task into upstream state
+func mergeState(snapshot, source, target data.Map) {
+	for k, v := range source {
+		if toolbox.IsFunc(v) {
+			continue
+		}
+		if prev, ok := snapshot[k]; ok && reflect.DeepEqual(prev, v) {
+			continue
+		}
+		target[k] = v
+	}
+}
+
+// runTaskGraph runs tasks honoring dependsOn, independent tasks run concurrently with bounded worker pool
+func (s *Service) runTaskGraph(context *endly.Context, process *model.Process, tasks *model.TasksNode) (err error) {
+	graph, err := newTaskGraph(tasks)
+	if err != nil {
+		return err
+	}
+	checkpoint := processCheckpoint(context, process)
+	for _, node := range graph.nodes {
+		if node.pending == 0 {
+			graph.schedule(node)
+		}
+	}
+	maxConcurrency := tasks.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = defaultTaskConcurrency
+	}
+	done := make(chan *graphNode, len(graph.nodes))
+	running := 0
+	var state = context.State()
+	var failed []*graphNode
+	complete := func(node *graphNode) {
+		if checkpoint != nil {
+			if e := checkpoint.Complete(context.Background(), process, node.task, state); e != nil && err == nil {
+				err = e
+			}
+		}
+		graph.resolve(node)
+	}
+	for {
+		for len(graph.ready) > 0 && running < maxConcurrency && err == nil && len(failed) == 0 && !process.IsTerminated() {
+			node := graph.next()
+			if checkpoint != nil && checkpoint.IsCompleted(node.task) {
+				context.Publish(msg.NewStdoutEvent("resume", fmt.Sprintf("skipping completed task: %v", node.task.Name)))
+				node.finished = true
+				graph.resolve(node)
+				continue
+			}
+			node.process = process.Fork()
+			node.context = context.Clone()
+			nodeProcesses := processes(context).Clone()
+			nodeProcesses.Push(node.process)
+			_ = node.context.Replace(processesKey, nodeProcesses)
+			nodeState := node.context.State()
+			if node.process.State != nil {
+				nodeState.Put(selfStateKey, node.process.State)
+				node.processSnapshot = data.NewMap()
+				node.processSnapshot.Apply(node.process.State)
+			}
+			node.snapshot = data.NewMap()
+			node.snapshot.Apply(nodeState)
+			node.events = node.context.MakeAsyncSafe()
+			running++
+			go func(node *graphNode) {
+				_, node.err = s.runTask(node.context, node.process, node.task)
+				done <- node
+			}(node)
+		}
+		if running == 0 {
+			if err != nil || len(failed) == 0 {
+				break
+			}
+			//catch task runs once per failed task after running tasks finished, recovered task releases its dependents
+			for _, node := range failed {
+				if err = s.runOnErrorTask(context, node.process, tasks, node.err); err != nil {
+					break
+				}
+				complete(node)
+			}
+			failed = nil
+			continue
+		}
+		node := <-done
+		running--
+		node.finished = true
+		if node.process.IsTerminated() {
+			process.Terminate()
+		}
+		if node.process.Scheduled != nil {
+			process.Scheduled = node.process.Scheduled
+		}
+		mergeState(node.snapshot, node.context.State(), state)
+		if node.processSnapshot != nil {
+			mergeState(node.processSnapshot, node.process.State, process.State)
+		}
+		graph.publish(context)
+		if node.err != nil {
+			failed = append(failed, node)
+			continue
+		}
+		complete(node)
+	}
+	for _, node := range graph.nodes {
+		node.finished = true
+	}
+	graph.publish(context)
+	return err
+}
@@ -0,0 +1,107 @@
+package workflow
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_RunTaskGraph(t *testing.T) {
+	var useCases = []struct {
+		description string
+		pipeline    string
+		expectCalls []string
+		expectEnded []string
+		expectMax   int32
+		hasError    bool
+	}{
+		{
+			description: "independent tasks run concurrently",
+			pipeline: `pipeline:
+  a:
+    action: probe:call
+    name: a
+    sleepMs: 100
+  b:
+    action: probe:call
+    name: b
+    sleepMs: 200
+  c:
+    action: probe:call
+    name: c
+    dependsOn: [a, b]
+`,
+			expectEnded: []string{"a", "b", "c"},
+			expectMax:   2,
+		},
+		{
+			description: "recovered task releases dependents",
+			pipeline: `pipeline:
+  a:
+    action: probe:call
+    name: a
+    failure: true
+  b:
+    action: probe:call
+    name: b
+    dependsOn: [a]
+  catch:
+    action: probe:call
+    name: catch
+`,
+			expectEnded: []string{"a", "catch", "b"},
+			expectMax:   1,
+		},
+		{
+			description: "catch runs once per failed task after running tasks finished",
+			pipeline: `pipeline:
+  a:
+    action: probe:call
+    name: a
+    sleepMs: 20
+    failure: true
+  b:
+    action: probe:call
+    name: b
+    sleepMs: 150
+    failure: true
+  c:
+    action: probe:call
+    name: c
+    dependsOn: [b]
+  catch:
+    action: probe:call
+    name: catch
+`,
+			expectEnded: []string{"a", "b", "catch", "catch", "c"},
+			expectMax:   2,
+		},
+		{
+			description: "failed task without catch stops graph",
+			pipeline: `pipeline:
+  a:
+    action: probe:call
+    name: a
+    failure: true
+  b:
+    action: probe:call
+    name: b
+    dependsOn: [a]
+`,
+			expectEnded: []string{"a"},
+			expectMax:   1,
+			hasError:    true,
+		},
+	}
+	for _, useCase := range useCases {
+		probe, _, response := runPipeline(t, useCase.pipeline, nil)
+		if useCase.hasError {
+			assert.NotEqual(t, "", response.Error, useCase.description)
+		} else {
+			assert.Equal(t, "", response.Error, useCase.description)
+		}
+		assert.EqualValues(t, useCase.expectEnded, probe.Ended(), useCase.description)
+		assert.Equal(t, useCase.expectMax, atomic.LoadInt32(&probe.maxRun), useCase.description)
+	}
+}
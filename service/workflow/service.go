@@ -8,6 +8,7 @@ import (
 	"path"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/viant/afs"
@@ -78,6 +79,13 @@ func (s *Service) addVariableEvent(name string, variables model.Variables, conte
 }
 
 func (s *Service) runAction(context *endly.Context, action *model.Action, process *model.Process) (response map[string]interface{}, err error) {
+	if action.Retry != nil {
+		return s.runActionWithRetry(context, action, process)
+	}
+	return s.runActionAttempt(context, action, process, 0)
+}
+
+func (s *Service) runActionAttempt(context *endly.Context, action *model.Action, process *model.Process, attempt int) (response map[string]interface{}, err error) {
 	var state = context.State()
 
 	var activity *model.Activity
@@ -85,6 +93,7 @@ func (s *Service) runAction(context *endly.Context, action *model.Action, proces
 		activity = model.NewActivity(context, action, state)
 		return nil
 	})
+	activity.Attempt = attempt
 	s.Mutex().Lock()
 	process.State.Put("index", action.TagIndex)
 	s.Mutex().Unlock()
@@ -108,31 +117,57 @@ func (s *Service) runAction(context *endly.Context, action *model.Action, proces
 			context.Publish(model.NewModifiedStateEvent(variables, state, state))
 		}
 	}()
-	var request interface{}
+	var listener = context.Listener
+	scope := &activityScope{}
+	defer scope.close()
 	err = s.runNode(context, "action", process, action.AbstractNode, func(context *endly.Context, process *model.Process) (in, out data.Map, err error) {
 		process.Push(activity)
 		startEvent := s.Begin(context, activity)
-		defer s.End(context)(startEvent, model.NewActivityEndEvent(activity))
-		defer process.Pop()
+		end := s.End(context.WithListener(listener))
+		scope.begin(func() {
+			process.Pop()
+			end(startEvent, model.NewActivityEndEvent(activity))
+		})
+		defer scope.close()
 
+		var request interface{}
+		serviceResponse := &endly.ServiceResponse{}
 		requestMap := toolbox.AsMap(activity.Request)
-		if err = runWithoutSelfIfNeeded(process, action, state, func() error {
-			request, err = context.AsRequest(activity.Service, activity.Action, requestMap)
-			return err
-		}); err != nil {
+		buildRequest := func() (interface{}, error) {
+			err := runWithoutSelfIfNeeded(process, action, state, func() error {
+				request, err = context.AsRequest(activity.Service, activity.Action, requestMap)
+				return err
+			})
+			return request, err
+		}
+		if _, err = buildRequest(); err != nil {
 			return nil, nil, err
 		}
-		err = endly.Run(context, request, activity.ServiceResponse)
+		switch {
+		case canRunDry(context, activity.Service, activity.Action):
+			err = s.runDry(context, activity, request, serviceResponse)
+		case context.Debugger != nil:
+			err = s.runDebuggable(context, process, action, request, buildRequest, serviceResponse)
+		default:
+			err = endly.Run(context, request, serviceResponse)
+		}
+		var result map[string]interface{}
+		scope.apply(func() {
+			activity.ServiceResponse = serviceResponse
+			if err != nil {
+				return
+			}
+			_ = toolbox.DefaultConverter.AssignConverted(&activity.Response, serviceResponse.Response)
+			result = activity.Response
+			if runResponse, ok := serviceResponse.Response.(*RunResponse); ok {
+				result = runResponse.Data
+			}
+			response = result
+		})
 		if err != nil {
 			return nil, nil, err
 		}
-
-		_ = toolbox.DefaultConverter.AssignConverted(&activity.Response, activity.ServiceResponse.Response)
-		response = activity.Response
-		if runResponse, ok := activity.ServiceResponse.Response.(*RunResponse); ok {
-			response = runResponse.Data
-		}
-		return response, state, err
+		return result, state, err
 	})
 	return response, err
 }
@@ -355,6 +390,7 @@ func (s *Service) enableLoggingIfNeeded(context *endly.Context, request *RunRequ
 
 		logger := NewLogger(logDirectory, context.Listener)
 		context.Listener = logger.AsEventListener()
+		context.LogDirectory = logDirectory
 	}
 }
 
@@ -398,12 +434,21 @@ func (s *Service) getWorkflow(context *endly.Context, request *RunRequest) (*mod
 }
 
 func (s *Service) runWorkflow(upstreamContext *endly.Context, request *RunRequest) (response *RunResponse, err error) {
+	if len(request.Matrix) > 0 {
+		return s.runMatrix(upstreamContext, request)
+	}
 	response = &RunResponse{
 		Data:      make(map[string]interface{}),
 		SessionID: upstreamContext.SessionID,
 	}
 
 	s.enableLoggingIfNeeded(upstreamContext, request)
+	s.enableDryRunIfNeeded(upstreamContext, request)
+	releaseDebugger, err := s.enableDebuggerIfNeeded(upstreamContext, request)
+	if err != nil {
+		return nil, err
+	}
+	defer releaseDebugger()
 	workflow, err := s.getWorkflow(upstreamContext, request)
 	if err != nil {
 		return nil, err
@@ -492,6 +537,20 @@ func (s *Service) runWorkflow(upstreamContext *endly.Context, request *RunReques
 		process.State.Put(dataStateKey, workflow.Data)
 	}
 
+	if upstreamProcess == nil {
+		checkpoint, err := s.initCheckpoint(context, request, workflow)
+		if err != nil {
+			return nil, err
+		}
+		if checkpoint != nil {
+			checkpoint.process = process
+			_ = context.Put(checkpointKey, checkpoint)
+			if request.Resume != "" {
+				checkpoint.Restore(process, state)
+			}
+		}
+	}
+
 	upstreamTasks, hasUpstreamTasks := state.GetValue(tasksStateKey)
 	restore := context.PublishAndRestore(toolbox.Pairs(
 		model.OwnerURL, workflow.Source.URL,
@@ -515,7 +574,12 @@ func (s *Service) runWorkflow(upstreamContext *endly.Context, request *RunReques
 		}
 	}
 	filteredTasks := workflow.TasksNode.Select(taskSelector)
-	err = s.runNode(context, "workflow", process, workflow.AbstractNode, func(context *endly.Context, process *model.Process) (in, out data.Map, err error) {
+	workflowNode := workflow.AbstractNode
+	if request.TimeoutMs > 0 {
+		workflowNode = workflowNode.Clone()
+		workflowNode.TimeoutMs = request.TimeoutMs
+	}
+	err = s.runNode(context, "workflow", process, workflowNode, func(context *endly.Context, process *model.Process) (in, out data.Map, err error) {
 		err = s.runTasks(context, process, filteredTasks)
 		return state, response.Data, err
 	})
@@ -547,7 +611,11 @@ func (s *Service) runNode(context *endly.Context, nodeType string, process *mode
 	if err != nil {
 		return err
 	}
-	in, out, err := runHandler(context, process)
+	if node.TimeoutMs > 0 {
+		restore := context.WithTimeout(fmt.Sprintf("%v %v", nodeType, node.Name), time.Duration(node.TimeoutMs)*time.Millisecond)
+		defer restore()
+	}
+	in, out, err := s.runHandler(context, nodeType, process, runHandler)
 	if err != nil {
 		return err
 	}
@@ -567,6 +635,8 @@ func (s *Service) runDeferredTask(context *endly.Context, process *model.Process
 	if parent.DeferredTask == "" {
 		return nil
 	}
+	restore := context.Detach()
+	defer restore()
 	task, _ := parent.Task(parent.DeferredTask)
 	_, err := s.runTask(context, process, task)
 	return err
@@ -593,6 +663,8 @@ func (s *Service) runOnErrorTask(context *endly.Context, process *model.Process,
 		if e != nil {
 			return fmt.Errorf("failed to catch: %v, %v", err, e)
 		}
+		restore := context.Detach()
+		defer restore()
 		//Reset workflow fail status by default
 		if !task.Fail {
 			context.Publish(&msg.ResetError{})
@@ -611,6 +683,13 @@ func (s *Service) runTasks(context *endly.Context, process *model.Process, tasks
 			err = e
 		}
 	}()
+	if tasks.HasDependencies() {
+		if err = s.runTaskGraph(context, process, tasks); err != nil {
+			return err
+		}
+		return s.runScheduledTask(context, process)
+	}
+	checkpoint := processCheckpoint(context, process)
 	for _, task := range tasks.Tasks {
 		if task.Name == tasks.OnErrorTask || task.Name == tasks.DeferredTask {
 			continue
@@ -618,19 +697,32 @@ func (s *Service) runTasks(context *endly.Context, process *model.Process, tasks
 		if process.IsTerminated() {
 			break
 		}
+		if checkpoint != nil && checkpoint.IsCompleted(task) {
+			context.Publish(msg.NewStdoutEvent("resume", fmt.Sprintf("skipping completed task: %v", task.Name)))
+			continue
+		}
 		if _, err = s.runTask(context, process, task); err != nil {
 			err = s.runOnErrorTask(context, process, tasks, err)
 		}
 		if err != nil {
 			return err
 		}
+		if checkpoint != nil {
+			if err = checkpoint.Complete(context.Background(), process, task, context.State()); err != nil {
+				return err
+			}
+		}
 	}
+	return s.runScheduledTask(context, process)
+}
+
+func (s *Service) runScheduledTask(context *endly.Context, process *model.Process) error {
 	var scheduledTask = process.Scheduled
-	if scheduledTask != nil {
-		process.Scheduled = nil
-		err = s.runTasks(context, process, &model.TasksNode{Tasks: []*model.Task{scheduledTask}})
+	if scheduledTask == nil {
+		return nil
 	}
-	return err
+	process.Scheduled = nil
+	return s.runTasks(context, process, &model.TasksNode{Tasks: []*model.Task{scheduledTask}})
 }
 
 func buildParamsMap(request *RunRequest, context *endly.Context) data.Map {
@@ -0,0 +1,155 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly"
+	"github.com/viant/endly/model/msg"
+)
+
+const probeServiceID = "probe"
+
+// probeRequest represents probe service request
+type probeRequest struct {
+	Name    string
+	SleepMs int
+	Failure bool
+}
+
+// probeResponse represents probe service response
+type probeResponse struct {
+	Name string
+}
+
+// probeEvent represents event published by probe handler after sleep
+type probeEvent struct {
+	Name string
+}
+
+// probeService records handler invocations, it is used to observe workflow service scheduling
+type probeService struct {
+	*endly.AbstractService
+	mux     sync.Mutex
+	calls   []string
+	ended   []string
+	running int32
+	maxRun  int32
+}
+
+func (s *probeService) Calls() []string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return append([]string{}, s.calls...)
+}
+
+func (s *probeService) Ended() []string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return append([]string{}, s.ended...)
+}
+
+func (s *probeService) handle(context *endly.Context, request *probeRequest) (*probeResponse, error) {
+	running := atomic.AddInt32(&s.running, 1)
+	defer atomic.AddInt32(&s.running, -1)
+	for {
+		max := atomic.LoadInt32(&s.maxRun)
+		if running <= max || atomic.CompareAndSwapInt32(&s.maxRun, max, running) {
+			break
+		}
+	}
+	s.mux.Lock()
+	s.calls = append(s.calls, request.Name)
+	s.mux.Unlock()
+	if request.SleepMs > 0 {
+		time.Sleep(time.Duration(request.SleepMs) * time.Millisecond)
+	}
+	s.mux.Lock()
+	s.ended = append(s.ended, request.Name)
+	s.mux.Unlock()
+	context.Publish(&probeEvent{Name: request.Name})
+	if request.Failure {
+		return nil, fmt.Errorf("%v failed", request.Name)
+	}
+	return &probeResponse{Name: request.Name}, nil
+}
+
+func newProbeService() *probeService {
+	result := &probeService{AbstractService: endly.NewAbstractService(probeServiceID)}
+	result.AbstractService.Service = result
+	result.Register(&endly.Route{
+		Action: "call",
+		RequestProvider: func() interface{} {
+			return &probeRequest{}
+		},
+		ResponseProvider: func() interface{} {
+			return &probeResponse{}
+		},
+		Handler: func(context *endly.Context, request interface{}) (interface{}, error) {
+			if req, ok := request.(*probeRequest); ok {
+				return result.handle(context, req)
+			}
+			return nil, fmt.Errorf("unsupported request type: %T", request)
+		},
+	})
+	return result
+}
+
+// eventRecorder collects published events
+type eventRecorder struct {
+	mux    sync.Mutex
+	events []msg.Event
+}
+
+func (r *eventRecorder) listen(event msg.Event) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) probes() []string {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	var result = make([]string, 0)
+	for _, event := range r.events {
+		if probe, ok := event.Value().(*probeEvent); ok {
+			result = append(result, probe.Name)
+		}
+	}
+	return result
+}
+
+// runPipeline runs inline workflow pipeline with the probe service registered
+func runPipeline(t *testing.T, pipeline string, update func(request *RunRequest)) (*probeService, *eventRecorder, *endly.ServiceResponse) {
+	location := filepath.Join(t.TempDir(), "pipeline.yaml")
+	if !assert.Nil(t, os.WriteFile(location, []byte(pipeline), 0644)) {
+		t.FailNow()
+	}
+	request, err := NewRunRequestFromURL(location)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	request.AssetURL = location
+	if update != nil {
+		update(request)
+	}
+	manager := endly.New()
+	probe := newProbeService()
+	manager.Register(probe)
+	context := manager.NewContext(nil)
+	defer context.Close()
+	recorder := &eventRecorder{}
+	context.Listener = recorder.listen
+	service, err := context.Service(ServiceID)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	response := service.Run(context, request)
+	return probe, recorder, response
+}
@@ -0,0 +1,97 @@
+package workflow
+
+import (
+	"sync"
+
+	"github.com/viant/endly"
+	"github.com/viant/endly/model"
+	"github.com/viant/endly/model/msg"
+	"github.com/viant/toolbox/data"
+)
+
+type nodeResult struct {
+	in  data.Map
+	out data.Map
+	err error
+}
+
+// eventGate forwards events to listener until closed, abandoned handler events are dropped
+type eventGate struct {
+	mux      sync.Mutex
+	closed   bool
+	listener msg.Listener
+}
+
+func (g *eventGate) publish(event msg.Event) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	if !g.closed && g.listener != nil {
+		g.listener(event)
+	}
+}
+
+func (g *eventGate) close() {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	g.closed = true
+}
+
+// activityScope ends action activity once, either when handler returns or when handler is abandoned on timeout
+type activityScope struct {
+	mux   sync.Mutex
+	ended bool
+	end   func()
+}
+
+func (a *activityScope) begin(end func()) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	a.end = end
+}
+
+// apply runs fn unless activity has already ended, abandoned handler can not modify activity or action response
+func (a *activityScope) apply(fn func()) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	if !a.ended {
+		fn()
+	}
+}
+
+func (a *activityScope) close() {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	if a.ended || a.end == nil {
+		return
+	}
+	a.ended = true
+	a.end()
+}
+
+// runHandler runs node handler, action handler is abandoned with timeout error once go context deadline is exceeded,
+// abandoned handler runs with go context that is already done and its events are no longer published
+func (s *Service) runHandler(context *endly.Context, nodeType string, process *model.Process, handler func(context *endly.Context, process *model.Process) (in, out data.Map, err error)) (data.Map, data.Map, error) {
+	ctx := context.Background()
+	if _, ok := ctx.Deadline(); !ok || nodeType != "action" {
+		return handler(context, process)
+	}
+	if err := context.TimeoutError(); err != nil {
+		return nil, nil, err
+	}
+	gate := &eventGate{listener: context.Listener}
+	handlerContext := context.WithListener(gate.publish)
+	done := make(chan *nodeResult, 1)
+	go func() {
+		in, out, err := handler(handlerContext, process)
+		done <- &nodeResult{in: in, out: out, err: err}
+	}()
+	select {
+	case result := <-done:
+		return result.in, result.out, result.err
+	case <-ctx.Done():
+		gate.close()
+		err := context.TimeoutError()
+		context.Publish(NewTimeoutEvent(err))
+		return nil, nil, err
+	}
+}
@@ -0,0 +1,43 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/viant/endly/model"
+)
+
+func TestService_RunTimeout(t *testing.T) {
+	pipeline := `pipeline:
+  slow:
+    action: probe:call
+    name: slow
+    sleepMs: 400
+`
+	started := time.Now()
+	probe, recorder, response := runPipeline(t, pipeline, func(request *RunRequest) {
+		request.TimeoutMs = 50
+	})
+	assert.True(t, time.Since(started) < 350*time.Millisecond, "run should not wait for abandoned handler")
+	assert.True(t, strings.Contains(response.Error, "timed out"), response.Error)
+	assert.EqualValues(t, []string{"slow"}, probe.Calls())
+
+	time.Sleep(500 * time.Millisecond)
+	assert.EqualValues(t, []string{}, recorder.probes(), "abandoned handler events should be dropped")
+
+	recorder.mux.Lock()
+	defer recorder.mux.Unlock()
+	var endEvents, timeoutEvents int
+	for _, event := range recorder.events {
+		switch event.Value().(type) {
+		case *model.ActivityEndEvent:
+			endEvents++
+		case *TimeoutEvent:
+			timeoutEvents++
+		}
+	}
+	assert.Equal(t, 1, endEvents, "action activity should end once")
+	assert.Equal(t, 1, timeoutEvents)
+}